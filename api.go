@@ -0,0 +1,43 @@
+package servicestack
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ApiResult is the outcome of an API call, holding either the response or
+// the error ResponseStatus
+type ApiResult[T any] struct {
+	Response   *T
+	Error      *ResponseStatus
+	StatusCode int
+}
+
+// IsSuccess reports whether the API call succeeded
+func (r ApiResult[T]) IsSuccess() bool {
+	return r.Error.IsSuccess()
+}
+
+// Api sends the request DTO using the method declared by its IGet, IPost,
+// IPut, IDelete or IPatch marker and POST otherwise, returning an ApiResult
+// instead of an error
+func Api[TResponse any](c *JsonServiceClient, request IReturn) ApiResult[TResponse] {
+	method := httpMethodOf(request, http.MethodPost)
+
+	response := new(TResponse)
+	resp, err := c.send(c.defaultContext(), method, request, response)
+	if err != nil {
+		var webEx *WebServiceException
+		if errors.As(err, &webEx) {
+			return ApiResult[TResponse]{Error: webEx.ResponseStatus, StatusCode: webEx.StatusCode}
+		}
+
+		result := ApiResult[TResponse]{Error: &ResponseStatus{ErrorCode: "Exception", Message: err.Error()}}
+		if resp != nil {
+			result.StatusCode = resp.StatusCode
+		}
+		return result
+	}
+
+	return ApiResult[TResponse]{Response: response, StatusCode: resp.StatusCode}
+}
@@ -0,0 +1,88 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApiSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, World!"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result := Api[HelloResponse](client, &Hello{Name: "World"})
+
+	if !result.IsSuccess() {
+		t.Fatalf("Expected success, got %v", result.Error)
+	}
+
+	if result.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code 200, got %d", result.StatusCode)
+	}
+
+	if result.Response.Result != "Hello, World!" {
+		t.Errorf("Expected result 'Hello, World!', got '%s'", result.Response.Result)
+	}
+}
+
+func TestApiValidationError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"responseStatus":{"errorCode":"NotEmpty","message":"'Name' must not be empty.",`+
+			`"errors":[{"errorCode":"NotEmpty","fieldName":"Name","message":"'Name' must not be empty."}]}}`)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result := Api[HelloResponse](client, &Hello{})
+
+	if result.IsSuccess() {
+		t.Fatal("Expected the API call to fail")
+	}
+
+	if result.Response != nil {
+		t.Errorf("Expected no response, got %v", result.Response)
+	}
+
+	if result.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status code 400, got %d", result.StatusCode)
+	}
+
+	if result.Error.ErrorCode != "NotEmpty" || result.Error.Errors[0].FieldName != "Name" {
+		t.Errorf("Expected a NotEmpty error for 'Name', got %v", result.Error)
+	}
+}
+
+func TestApiUsesGetForIGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	if result := Api[HelloResponse](client, &GetHello{Name: "World"}); !result.IsSuccess() {
+		t.Fatalf("Expected success, got %v", result.Error)
+	}
+}
+
+func TestResponseStatusIsSuccess(t *testing.T) {
+	var status *ResponseStatus
+	if !status.IsSuccess() {
+		t.Error("Expected nil status to be successful")
+	}
+
+	if (&ResponseStatus{ErrorCode: "NotFound"}).IsSuccess() {
+		t.Error("Expected status with an error code not to be successful")
+	}
+}
@@ -0,0 +1,72 @@
+package servicestack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Result is the outcome of an asynchronous request
+type Result struct {
+	Value interface{}
+	Err   error
+}
+
+// SendAsync sends the request in a new goroutine, delivering its Result on
+// the returned channel
+func (c *JsonServiceClient) SendAsync(method string, request interface{}, responseType interface{}) <-chan Result {
+	results := make(chan Result, 1)
+	go func() {
+		value, err := c.Send(method, request, responseType)
+		results <- Result{Value: value, Err: err}
+		close(results)
+	}()
+	return results
+}
+
+// GetAsync sends the request DTO as an asynchronous GET request
+func (c *JsonServiceClient) GetAsync(request IReturn) <-chan Result {
+	return c.SendAsync(http.MethodGet, request, request.ResponseType())
+}
+
+// PostAsync sends the request DTO as an asynchronous POST request
+func (c *JsonServiceClient) PostAsync(request IReturn) <-chan Result {
+	return c.SendAsync(http.MethodPost, request, request.ResponseType())
+}
+
+// PutAsync sends the request DTO as an asynchronous PUT request
+func (c *JsonServiceClient) PutAsync(request IReturn) <-chan Result {
+	return c.SendAsync(http.MethodPut, request, request.ResponseType())
+}
+
+// DeleteAsync sends the request DTO as an asynchronous DELETE request
+func (c *JsonServiceClient) DeleteAsync(request IReturn) <-chan Result {
+	return c.SendAsync(http.MethodDelete, request, request.ResponseType())
+}
+
+// PatchAsync sends the request DTO as an asynchronous PATCH request
+func (c *JsonServiceClient) PatchAsync(request IReturn) <-chan Result {
+	return c.SendAsync(http.MethodPatch, request, request.ResponseType())
+}
+
+// acquireSlot waits until fewer than MaxConcurrency requests are in flight,
+// returning a func that releases the slot
+func (c *JsonServiceClient) acquireSlot(ctx context.Context) (func(), error) {
+	if c.MaxConcurrency <= 0 {
+		return func() {}, nil
+	}
+
+	c.state.slotsMu.Lock()
+	if cap(c.state.slots) != c.MaxConcurrency {
+		c.state.slots = make(chan struct{}, c.MaxConcurrency)
+	}
+	slots := c.state.slots
+	c.state.slotsMu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to execute request: %w", ctx.Err())
+	}
+}
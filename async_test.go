@@ -0,0 +1,96 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetAsyncFanOut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, " + r.URL.Query().Get("name") + "!"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	channels := []<-chan Result{
+		client.GetAsync(&Hello{Name: "A"}),
+		client.GetAsync(&Hello{Name: "B"}),
+		client.GetAsync(&Hello{Name: "C"}),
+	}
+
+	var results []string
+	for _, ch := range channels {
+		result := <-ch
+		if result.Err != nil {
+			t.Fatalf("Expected no error, got %v", result.Err)
+		}
+		results = append(results, result.Value.(*HelloResponse).Result)
+	}
+	sort.Strings(results)
+
+	expected := []string{"Hello, A!", "Hello, B!", "Hello, C!"}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Errorf("Expected result '%s', got '%s'", expected[i], results[i])
+		}
+	}
+}
+
+func TestPostAsyncError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result := <-client.PostAsync(&Hello{Name: "A"})
+
+	if result.Err == nil {
+		t.Fatal("Expected an error for 500 status code")
+	}
+
+	if result.Value != nil {
+		t.Errorf("Expected no value, got %v", result.Value)
+	}
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.MaxConcurrency = 2
+
+	var results []<-chan Result
+	for i := 0; i < 8; i++ {
+		results = append(results, client.GetAsync(&Hello{}))
+	}
+	for _, result := range results {
+		if r := <-result; r.Err != nil {
+			t.Fatalf("Expected no error, got %v", r.Err)
+		}
+	}
+
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 requests in flight, got %d", maxInFlight)
+	}
+}
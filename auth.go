@@ -0,0 +1,261 @@
+package servicestack
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// AuthenticateRequest is ServiceStack's Authenticate request DTO
+type AuthenticateRequest struct {
+	Provider string `json:"provider,omitempty"`
+	UserName string `json:"userName,omitempty"`
+	Password string `json:"password,omitempty" servicestack:"secret"`
+	// RememberMe requests a persistent session that survives the session
+	// cookie's expiry, stored in the client's cookie jar
+	RememberMe  bool              `json:"rememberMe,omitempty"`
+	AccessToken string            `json:"accessToken,omitempty" servicestack:"secret"`
+	Meta        map[string]string `json:"meta,omitempty"`
+}
+
+// ResponseType returns the AuthenticateResponse
+func (r *AuthenticateRequest) ResponseType() interface{} {
+	return &AuthenticateResponse{}
+}
+
+// AuthenticateResponse is ServiceStack's Authenticate response DTO
+type AuthenticateResponse struct {
+	UserId         string            `json:"userId,omitempty"`
+	SessionId      string            `json:"sessionId,omitempty"`
+	UserName       string            `json:"userName,omitempty"`
+	DisplayName    string            `json:"displayName,omitempty"`
+	BearerToken    string            `json:"bearerToken,omitempty"`
+	RefreshToken   string            `json:"refreshToken,omitempty"`
+	Roles          []string          `json:"roles,omitempty"`
+	Permissions    []string          `json:"permissions,omitempty"`
+	Meta           map[string]string `json:"meta,omitempty"`
+	ResponseStatus *ResponseStatus   `json:"responseStatus,omitempty"`
+}
+
+// Authenticate sends the request to ServiceStack's Authenticate service. The
+// session cookies it returns, including the persistent ones requested with
+// RememberMe, are stored in the client's cookie jar, and the returned
+// BearerToken and RefreshToken are used for subsequent requests.
+func (c *JsonServiceClient) Authenticate(request *AuthenticateRequest) (*AuthenticateResponse, error) {
+	response := &AuthenticateResponse{}
+	if _, err := c.sendJSON(c.defaultContext(), http.MethodPost, c.typePath("Authenticate"), request, response); err != nil {
+		return nil, err
+	}
+
+	if response.BearerToken != "" {
+		c.SetBearerToken(response.BearerToken)
+	}
+	if response.RefreshToken != "" {
+		c.setRefreshToken(response.RefreshToken)
+	}
+	c.captureSessionId(response.SessionId)
+	return response, nil
+}
+
+// Login authenticates with the credentials auth provider, relying on the
+// session cookies stored in the client's cookie jar, or the X-ss-id header
+// when the HTTPClient has no cookie jar, to authenticate subsequent requests
+// instead of a bearer token
+func (c *JsonServiceClient) Login(userName, password string) error {
+	request := &AuthenticateRequest{Provider: "credentials", UserName: userName, Password: password}
+	response := &AuthenticateResponse{}
+	if _, err := c.sendJSON(c.defaultContext(), http.MethodPost, c.typePath("Authenticate"), request, response); err != nil {
+		return err
+	}
+	c.captureSessionId(response.SessionId)
+	return nil
+}
+
+// captureSessionId sends the authenticated session id in the X-ss-id header
+// when the client can't store session cookies
+func (c *JsonServiceClient) captureSessionId(sessionId string) {
+	if sessionId != "" && c.HTTPClient.Jar == nil {
+		c.SetSessionId(sessionId)
+	}
+}
+
+// ConvertSessionToTokenRequest is ServiceStack's ConvertSessionToToken request DTO
+type ConvertSessionToTokenRequest struct {
+	PreserveSession bool `json:"preserveSession,omitempty"`
+}
+
+// ConvertSessionToTokenResponse is ServiceStack's ConvertSessionToToken response DTO
+type ConvertSessionToTokenResponse struct {
+	AccessToken    string            `json:"accessToken,omitempty"`
+	RefreshToken   string            `json:"refreshToken,omitempty"`
+	Meta           map[string]string `json:"meta,omitempty"`
+	ResponseStatus *ResponseStatus   `json:"responseStatus,omitempty"`
+}
+
+// ConvertSessionToToken converts the client's authenticated session into a
+// JWT, stored by the server in the ss-tok cookie. Tokens returned in the
+// response are used as the client's BearerToken and RefreshToken.
+func (c *JsonServiceClient) ConvertSessionToToken() error {
+	response := &ConvertSessionToTokenResponse{}
+	path := c.typePath("ConvertSessionToToken")
+	if _, err := c.sendJSON(c.defaultContext(), http.MethodPost, path, &ConvertSessionToTokenRequest{}, response); err != nil {
+		return err
+	}
+
+	if response.AccessToken != "" {
+		c.SetBearerToken(response.AccessToken)
+	}
+	if response.RefreshToken != "" {
+		c.setRefreshToken(response.RefreshToken)
+	}
+	return nil
+}
+
+// TokenExpiry returns the expiry of the BearerToken from its JWT exp claim,
+// reporting false when there's no token or it isn't a JWT with an exp claim.
+// The token's signature isn't verified.
+func (c *JsonServiceClient) TokenExpiry() (time.Time, bool) {
+	c.state.headersMu.RLock()
+	token := c.BearerToken
+	c.state.headersMu.RUnlock()
+	return jwtExpiry(token)
+}
+
+// jwtExpiry decodes the exp claim of the JWT's payload
+func jwtExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(exp), 0), true
+}
+
+// ErrNoRefreshToken is returned when refreshing the BearerToken without a
+// RefreshToken
+var ErrNoRefreshToken = errors.New("servicestack: no refresh token")
+
+// GetAccessTokenRequest is ServiceStack's GetAccessToken request DTO
+type GetAccessTokenRequest struct {
+	RefreshToken string            `json:"refreshToken,omitempty" servicestack:"secret"`
+	Meta         map[string]string `json:"meta,omitempty"`
+}
+
+// GetAccessTokenResponse is ServiceStack's GetAccessToken response DTO
+type GetAccessTokenResponse struct {
+	AccessToken    string            `json:"accessToken,omitempty"`
+	Meta           map[string]string `json:"meta,omitempty"`
+	ResponseStatus *ResponseStatus   `json:"responseStatus,omitempty"`
+}
+
+// RefreshAccessToken requests a new BearerToken with the RefreshToken from
+// ServiceStack's GetAccessToken service
+func (c *JsonServiceClient) RefreshAccessToken() error {
+	refreshToken := c.refreshToken()
+	if refreshToken == "" {
+		return ErrNoRefreshToken
+	}
+
+	response := &GetAccessTokenResponse{}
+	request := &GetAccessTokenRequest{RefreshToken: refreshToken}
+	if _, err := c.sendJSON(c.defaultContext(), http.MethodPost, c.typePath("GetAccessToken"), request, response); err != nil {
+		return err
+	}
+	if response.AccessToken != "" {
+		c.SetBearerToken(response.AccessToken)
+	}
+	return nil
+}
+
+// refreshToken returns the RefreshToken, which is updated while requests
+// are in flight
+func (c *JsonServiceClient) refreshToken() string {
+	c.state.headersMu.RLock()
+	defer c.state.headersMu.RUnlock()
+	return c.RefreshToken
+}
+
+// setRefreshToken sets the RefreshToken returned by an auth service
+func (c *JsonServiceClient) setRefreshToken(token string) {
+	c.state.headersMu.Lock()
+	defer c.state.headersMu.Unlock()
+	c.RefreshToken = token
+}
+
+// refreshExpiringToken refreshes the BearerToken before a request when it
+// expires within RefreshMargin
+func (c *JsonServiceClient) refreshExpiringToken() error {
+	if c.RefreshMargin <= 0 || c.refreshToken() == "" {
+		return nil
+	}
+	expiry, ok := c.TokenExpiry()
+	if !ok || c.getClock().Now().Add(c.RefreshMargin).Before(expiry) {
+		return nil
+	}
+
+	// The GetAccessToken request itself isn't refreshed again
+	if !c.state.refreshing.CompareAndSwap(false, true) {
+		return nil
+	}
+	defer c.state.refreshing.Store(false)
+	if err := c.RefreshAccessToken(); err != nil {
+		return fmt.Errorf("failed to refresh access token: %w", err)
+	}
+	return nil
+}
+
+// answerBasicAuthChallenge asks OnBasicAuthChallenge for credentials when the
+// response is a 401 with a Basic challenge, reporting whether they were set
+func (c *JsonServiceClient) answerBasicAuthChallenge(resp *http.Response) bool {
+	if c.OnBasicAuthChallenge == nil || resp.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+
+	for _, challenge := range resp.Header.Values("WWW-Authenticate") {
+		realm, ok := parseBasicChallenge(challenge)
+		if !ok {
+			continue
+		}
+		user, pass, ok := c.OnBasicAuthChallenge(realm)
+		if !ok {
+			return false
+		}
+		c.SetCredentials(user, pass)
+		return true
+	}
+	return false
+}
+
+// parseBasicChallenge returns the realm of a Basic WWW-Authenticate challenge
+func parseBasicChallenge(challenge string) (realm string, ok bool) {
+	scheme, params, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	if !strings.EqualFold(scheme, "Basic") {
+		return "", false
+	}
+
+	for _, param := range strings.Split(params, ",") {
+		name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found && strings.EqualFold(name, "realm") {
+			return strings.Trim(value, `"`), true
+		}
+	}
+	return "", true
+}
@@ -0,0 +1,525 @@
+package servicestack
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBasicAuthChallenge(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "admin" || pass != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="/auth/basic"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, " + user + "!"})
+	}))
+	defer server.Close()
+
+	var challengedRealm string
+	client := NewJsonServiceClient(server.URL)
+	client.OnBasicAuthChallenge = func(realm string) (string, string, bool) {
+		challengedRealm = realm
+		return "admin", "secret", true
+	}
+
+	result, err := client.Get(&Hello{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.(*HelloResponse).Result != "Hello, admin!" {
+		t.Errorf("Expected result 'Hello, admin!', got '%s'", result.(*HelloResponse).Result)
+	}
+
+	if challengedRealm != "/auth/basic" {
+		t.Errorf("Expected realm '/auth/basic', got '%s'", challengedRealm)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestBasicAuthChallengeConcurrentRequests(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="api"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.OnBasicAuthChallenge = func(realm string) (string, string, bool) {
+		return "admin", "secret", true
+	}
+
+	// Challenges update the client's headers while other requests read them
+	var channels []<-chan Result
+	for i := 0; i < 30; i++ {
+		channels = append(channels, client.GetAsync(&Hello{}))
+	}
+	for _, ch := range channels {
+		if result := <-ch; result.Err != nil {
+			t.Errorf("Expected no error, got %v", result.Err)
+		}
+	}
+}
+
+func TestBasicAuthChallengeRetriesOnce(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("WWW-Authenticate", `Basic realm="api"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.OnBasicAuthChallenge = func(realm string) (string, string, bool) {
+		return "admin", "wrong", true
+	}
+
+	_, err := client.Get(&Hello{})
+	if webEx, ok := err.(*WebServiceException); !ok || webEx.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected a 401 WebServiceException, got %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestParseBasicChallenge(t *testing.T) {
+	if realm, ok := parseBasicChallenge(`Basic realm="test", charset="UTF-8"`); !ok || realm != "test" {
+		t.Errorf("Expected realm 'test', got '%s'", realm)
+	}
+
+	if _, ok := parseBasicChallenge(`Bearer realm="test"`); ok {
+		t.Error("Expected Bearer challenge not to be parsed as Basic")
+	}
+}
+
+func TestJsonServiceClientAuthenticateRememberMe(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json/reply/Authenticate" {
+			t.Errorf("Expected path '/json/reply/Authenticate', got '%s'", r.URL.Path)
+		}
+
+		var request map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&request)
+		if request["rememberMe"] != true {
+			t.Errorf("Expected rememberMe true, got %v", request["rememberMe"])
+		}
+		if request["userName"] != "user" {
+			t.Errorf("Expected userName 'user', got %v", request["userName"])
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "ss-opt", Value: "perm", Path: "/", Expires: time.Now().Add(24 * time.Hour)})
+		http.SetCookie(w, &http.Cookie{Name: "ss-pid", Value: "permanent-session", Path: "/", Expires: time.Now().Add(24 * time.Hour)})
+		json.NewEncoder(w).Encode(AuthenticateResponse{UserId: "1", SessionId: "permanent-session"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	response, err := client.Authenticate(&AuthenticateRequest{
+		Provider:   "credentials",
+		UserName:   "user",
+		Password:   "pass",
+		RememberMe: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.SessionId != "permanent-session" {
+		t.Errorf("Expected session id 'permanent-session', got '%s'", response.SessionId)
+	}
+	if client.GetTokenCookie("ss-opt") != "perm" {
+		t.Errorf("Expected ss-opt cookie 'perm', got '%s'", client.GetTokenCookie("ss-opt"))
+	}
+	if client.GetTokenCookie("ss-pid") != "permanent-session" {
+		t.Errorf("Expected ss-pid cookie 'permanent-session', got '%s'", client.GetTokenCookie("ss-pid"))
+	}
+}
+
+func TestJsonServiceClientAuthenticateStoresTokens(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/json/reply/Authenticate" {
+			w.Write([]byte(`{"userId":"1","bearerToken":"jwt-token","refreshToken":"refresh-token"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: r.Header.Get("Authorization")})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.Authenticate(&AuthenticateRequest{Provider: "credentials", UserName: "user", Password: "pass"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if client.BearerToken != "jwt-token" {
+		t.Errorf("Expected BearerToken 'jwt-token', got '%s'", client.BearerToken)
+	}
+	if client.RefreshToken != "refresh-token" {
+		t.Errorf("Expected RefreshToken 'refresh-token', got '%s'", client.RefreshToken)
+	}
+
+	result, err := client.Get(&Hello{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.(*HelloResponse).Result != "Bearer jwt-token" {
+		t.Errorf("Expected Authorization 'Bearer jwt-token', got '%s'", result.(*HelloResponse).Result)
+	}
+}
+
+func TestJsonServiceClientConvertSessionToToken(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+		if r.URL.Path != "/json/reply/ConvertSessionToToken" {
+			t.Errorf("Expected path '/json/reply/ConvertSessionToToken', got '%s'", r.URL.Path)
+		}
+		if cookie, err := r.Cookie("ss-id"); err != nil || cookie.Value != "session" {
+			t.Errorf("Expected ss-id session cookie, got %v", cookie)
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: TokenCookie, Value: "jwt-token", Path: "/"})
+		json.NewEncoder(w).Encode(ConvertSessionToTokenResponse{AccessToken: "jwt-token", RefreshToken: "refresh-token"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetTokenCookie("ss-id", "session")
+
+	if err := client.ConvertSessionToToken(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if client.GetTokenCookie(TokenCookie) != "jwt-token" {
+		t.Errorf("Expected ss-tok cookie 'jwt-token', got '%s'", client.GetTokenCookie(TokenCookie))
+	}
+	if client.BearerToken != "jwt-token" {
+		t.Errorf("Expected BearerToken 'jwt-token', got '%s'", client.BearerToken)
+	}
+	if client.RefreshToken != "refresh-token" {
+		t.Errorf("Expected RefreshToken 'refresh-token', got '%s'", client.RefreshToken)
+	}
+}
+
+func TestJsonServiceClientLoginUsesSessionCookie(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json/reply/Authenticate":
+			var request AuthenticateRequest
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.Provider != "credentials" || request.UserName != "user" || request.Password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "ss-id", Value: "session-1", Path: "/"})
+			json.NewEncoder(w).Encode(AuthenticateResponse{SessionId: "session-1"})
+		case "/json/reply/Hello":
+			cookie, err := r.Cookie("ss-id")
+			if err != nil || cookie.Value != "session-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if auth := r.Header.Get("Authorization"); auth != "" {
+				t.Errorf("Expected no Authorization header, got '%s'", auth)
+			}
+			json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, user!"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.Get(&Hello{}); err == nil {
+		t.Fatal("Expected an error before logging in")
+	}
+
+	if err := client.Login("user", "pass"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	result, err := client.Get(&Hello{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.(*HelloResponse).Result != "Hello, user!" {
+		t.Errorf("Expected result 'Hello, user!', got '%s'", result.(*HelloResponse).Result)
+	}
+
+	if client.BearerToken != "" {
+		t.Errorf("Expected no bearer token, got '%s'", client.BearerToken)
+	}
+}
+
+func TestJsonServiceClientOnAuthenticationRequired(t *testing.T) {
+	helloRequests := 0
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json/reply/Authenticate":
+			http.SetCookie(w, &http.Cookie{Name: "ss-id", Value: "session-2", Path: "/"})
+			json.NewEncoder(w).Encode(AuthenticateResponse{SessionId: "session-2"})
+		case "/json/reply/Hello":
+			helloRequests++
+			if cookie, err := r.Cookie("ss-id"); err != nil || cookie.Value != "session-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	reauthentications := 0
+	client.OnAuthenticationRequired = func() error {
+		reauthentications++
+		return client.Login("user", "pass")
+	}
+
+	result, err := client.Get(&Hello{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.(*HelloResponse).Result != "OK" {
+		t.Errorf("Expected result 'OK', got '%s'", result.(*HelloResponse).Result)
+	}
+	if reauthentications != 1 {
+		t.Errorf("Expected 1 reauthentication, got %d", reauthentications)
+	}
+	if helloRequests != 2 {
+		t.Errorf("Expected 2 requests, got %d", helloRequests)
+	}
+}
+
+func TestJsonServiceClientOnAuthenticationRequiredRetriesOnce(t *testing.T) {
+	requests := 0
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	reauthentications := 0
+	client.OnAuthenticationRequired = func() error {
+		reauthentications++
+		return client.Login("user", "wrong")
+	}
+
+	if _, err := client.Get(&Hello{}); !errors.Is(err, ErrNotAuthenticated) {
+		t.Fatalf("Expected ErrNotAuthenticated, got %v", err)
+	}
+
+	if reauthentications != 1 {
+		t.Errorf("Expected 1 reauthentication, got %d", reauthentications)
+	}
+}
+
+func TestJsonServiceClientSetSessionId(t *testing.T) {
+	var sessionId string
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionId = r.Header.Get("X-ss-id")
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetSessionId("session-1")
+
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if sessionId != "session-1" {
+		t.Errorf("Expected X-ss-id 'session-1', got '%s'", sessionId)
+	}
+}
+
+func TestJsonServiceClientLoginCapturesSessionIdWithoutCookieJar(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json/reply/Authenticate":
+			http.SetCookie(w, &http.Cookie{Name: "ss-id", Value: "session-3", Path: "/"})
+			json.NewEncoder(w).Encode(AuthenticateResponse{SessionId: "session-3"})
+		case "/json/reply/Hello":
+			if r.Header.Get("X-ss-id") != "session-3" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.HTTPClient.Jar = nil
+
+	if err := client.Login("user", "pass"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if client.SessionId != "session-3" {
+		t.Errorf("Expected SessionId 'session-3', got '%s'", client.SessionId)
+	}
+
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+// unsignedJWT returns an unsigned JWT with the claims as its payload
+func unsignedJWT(claims string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	return header + "." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + "."
+}
+
+func TestJsonServiceClientTokenExpiry(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+	if _, ok := client.TokenExpiry(); ok {
+		t.Error("Expected no expiry without a bearer token")
+	}
+
+	client.SetBearerToken(unsignedJWT(`{"sub":"1","exp":1704067200}`))
+	expiry, ok := client.TokenExpiry()
+	if !ok {
+		t.Fatal("Expected the token's expiry")
+	}
+	if expected := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !expiry.Equal(expected) {
+		t.Errorf("Expected expiry %v, got %v", expected, expiry.UTC())
+	}
+
+	for _, token := range []string{"opaque-token", unsignedJWT(`{"sub":"1"}`), "a.!!!.c"} {
+		client.SetBearerToken(token)
+		if _, ok := client.TokenExpiry(); ok {
+			t.Errorf("Expected no expiry for token '%s'", token)
+		}
+	}
+}
+
+func TestJsonServiceClientRefreshMargin(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiring := unsignedJWT(fmt.Sprintf(`{"exp":%d}`, now.Add(30*time.Second).Unix()))
+	refreshed := unsignedJWT(fmt.Sprintf(`{"exp":%d}`, now.Add(time.Hour).Unix()))
+
+	refreshes := 0
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/json/reply/GetAccessToken" {
+			refreshes++
+			var request GetAccessTokenRequest
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.RefreshToken != "refresh" {
+				t.Errorf("Expected refresh token 'refresh', got '%s'", request.RefreshToken)
+			}
+			json.NewEncoder(w).Encode(GetAccessTokenResponse{AccessToken: refreshed})
+			return
+		}
+
+		if auth := r.Header.Get("Authorization"); auth != "Bearer "+refreshed {
+			t.Errorf("Expected the refreshed token, got '%s'", auth)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.clock = &fakeClock{now: now}
+	client.SetBearerToken(expiring)
+	client.RefreshToken = "refresh"
+	client.RefreshMargin = time.Minute
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(&Hello{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if refreshes != 1 {
+		t.Errorf("Expected 1 refresh, got %d", refreshes)
+	}
+	if client.BearerToken != refreshed {
+		t.Errorf("Expected BearerToken to be the refreshed token, got '%s'", client.BearerToken)
+	}
+}
+
+func TestJsonServiceClientRefreshMarginFailure(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json/reply/GetAccessToken" {
+			t.Errorf("Expected no request after a failed refresh, got '%s'", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetBearerToken(unsignedJWT(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(-time.Minute).Unix())))
+	client.RefreshToken = "expired"
+	client.RefreshMargin = time.Minute
+
+	if _, err := client.Get(&Hello{}); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("Expected ErrNotAuthenticated from the refresh, got %v", err)
+	}
+}
+
+func TestJsonServiceClientRefreshMarginConcurrentRequests(t *testing.T) {
+	expiring := unsignedJWT(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(10*time.Second).Unix()))
+	refreshed := unsignedJWT(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(time.Hour).Unix()))
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/json/reply/GetAccessToken" {
+			json.NewEncoder(w).Encode(GetAccessTokenResponse{AccessToken: refreshed})
+			return
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetBearerToken(expiring)
+	client.RefreshToken = "refresh"
+	client.RefreshMargin = time.Minute
+
+	// Refreshes update the bearer token while other requests read it
+	var channels []<-chan Result
+	for i := 0; i < 30; i++ {
+		channels = append(channels, client.GetAsync(&Hello{}))
+	}
+	for _, ch := range channels {
+		if result := <-ch; result.Err != nil {
+			t.Errorf("Expected no error, got %v", result.Err)
+		}
+	}
+	if expiry, ok := client.TokenExpiry(); !ok || time.Until(expiry) < time.Minute {
+		t.Errorf("Expected the refreshed token, got expiry %v", expiry)
+	}
+}
@@ -0,0 +1,93 @@
+package servicestack
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// RequestBuilder builds a one-off request with a fluent API, created by
+// JsonServiceClient.Request
+type RequestBuilder struct {
+	client  *JsonServiceClient
+	method  string
+	path    string
+	body    interface{}
+	headers map[string]string
+	query   url.Values
+}
+
+// Request returns a RequestBuilder for a one-off request, e.g.
+//
+//	client.Request().Method("POST").Path("/json/reply/Hello").Body(req).Header("X-Foo", "bar").Send(&resp)
+func (c *JsonServiceClient) Request() *RequestBuilder {
+	return &RequestBuilder{client: c, headers: map[string]string{}, query: url.Values{}}
+}
+
+// Method sets the HTTP method, defaulting to the method declared by the
+// body's marker interface, POST for other bodies and GET without a body
+func (b *RequestBuilder) Method(method string) *RequestBuilder {
+	b.method = method
+	return b
+}
+
+// Path sets the path relative to BaseURL, defaulting to the body's route
+func (b *RequestBuilder) Path(path string) *RequestBuilder {
+	b.path = path
+	return b
+}
+
+// Body sets the request DTO
+func (b *RequestBuilder) Body(body interface{}) *RequestBuilder {
+	b.body = body
+	return b
+}
+
+// Header sets a header sent with the request, overriding the client's headers
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	b.headers[key] = value
+	return b
+}
+
+// Query adds a query string param sent with the request
+func (b *RequestBuilder) Query(name, value string) *RequestBuilder {
+	b.query.Add(name, value)
+	return b
+}
+
+// Send sends the request, unmarshalling the response into the provided
+// pointer. Requests without a Path are sent to the body's route like Send.
+func (b *RequestBuilder) Send(response interface{}) error {
+	c := b.client
+	method := b.method
+	if method == "" {
+		method = http.MethodGet
+		if b.body != nil {
+			method = httpMethodOf(b.body, http.MethodPost)
+		}
+	}
+
+	ctx := c.defaultContext()
+	for key, value := range b.headers {
+		ctx = withRequestHeader(ctx, key, value)
+	}
+	if len(b.query) > 0 {
+		ctx = context.WithValue(ctx, requestQueryKey{}, QueryParams(b.query))
+	}
+
+	if b.path == "" {
+		_, err := c.send(ctx, method, b.body, response)
+		return err
+	}
+
+	path, body := b.path, b.body
+	if !hasRequestBody(method) && body != nil {
+		if queryString := toQueryString(body); queryString != "" {
+			path += "?" + queryString
+		}
+		body = nil
+	}
+	path = appendRequestQuery(ctx, path)
+	_, err := c.sendJSON(ctx, method, path, body, response)
+	return err
+}
@@ -0,0 +1,86 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestBuilder(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+		if r.URL.Path != "/json/reply/Hello" {
+			t.Errorf("Expected path '/json/reply/Hello', got '%s'", r.URL.Path)
+		}
+		if r.Header.Get("X-Foo") != "bar" {
+			t.Errorf("Expected X-Foo header 'bar', got '%s'", r.Header.Get("X-Foo"))
+		}
+		if r.URL.Query().Get("debug") != "true" {
+			t.Errorf("Expected debug query param 'true', got '%s'", r.URL.Query().Get("debug"))
+		}
+
+		var request Hello
+		json.NewDecoder(r.Body).Decode(&request)
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, " + request.Name + "!"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	var response HelloResponse
+	err := client.Request().
+		Method("POST").
+		Path("/json/reply/Hello").
+		Body(&Hello{Name: "World"}).
+		Header("X-Foo", "bar").
+		Query("debug", "true").
+		Send(&response)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Result != "Hello, World!" {
+		t.Errorf("Expected result 'Hello, World!', got '%s'", response.Result)
+	}
+
+	if _, ok := client.Headers["X-Foo"]; ok {
+		t.Error("Expected the request header not to be added to the client's headers")
+	}
+}
+
+func TestRequestBuilderDefaults(t *testing.T) {
+	var method, path, query string
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, query = r.Method, r.URL.Path, r.URL.RawQuery
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+
+	var response HelloResponse
+	if err := client.Request().Body(&GetHello{Name: "World"}).Send(&response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if method != http.MethodGet || path != "/json/reply/GetHello" || query != "name=World" {
+		t.Errorf("Expected GET /json/reply/GetHello?name=World, got %s %s?%s", method, path, query)
+	}
+
+	if err := client.Request().Body(&Hello{Name: "World"}).Send(&response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if method != http.MethodPost || path != "/json/reply/Hello" {
+		t.Errorf("Expected POST /json/reply/Hello, got %s %s", method, path)
+	}
+
+	if err := client.Request().Path("/health").Query("verbose", "1").Send(&response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if method != http.MethodGet || path != "/health" || query != "verbose=1" {
+		t.Errorf("Expected GET /health?verbose=1, got %s %s?%s", method, path, query)
+	}
+}
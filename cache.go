@@ -0,0 +1,78 @@
+package servicestack
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache stores GET responses keyed by request URL so they can be revalidated
+// with their ETag. Requests overriding the Accept header, e.g. GetCSV, prefix
+// the URL with their Accept header.
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// CacheEntry is a cached response body and the ETag it was returned with
+type CacheEntry struct {
+	ETag string
+	Body []byte
+}
+
+// MemoryCache is an in-memory Cache whose entries expire after a TTL
+type MemoryCache struct {
+	TTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	entry   *CacheEntry
+	expires time.Time
+}
+
+// NewMemoryCache creates a MemoryCache whose entries expire after ttl, 0 means never
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		TTL:     ttl,
+		entries: make(map[string]memoryCacheEntry),
+	}
+}
+
+// Get returns the unexpired entry for the key
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cached, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !cached.expires.IsZero() && time.Now().After(cached.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return cached.entry, true
+}
+
+// Set stores the entry for the key
+func (m *MemoryCache) Set(key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cached := memoryCacheEntry{entry: entry}
+	if m.TTL > 0 {
+		cached.expires = time.Now().Add(m.TTL)
+	}
+	m.entries[key] = cached
+}
+
+// Delete removes the entry for the key
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+}
@@ -0,0 +1,76 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, World!"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.Cache = NewMemoryCache(time.Minute)
+
+	for i := 0; i < 2; i++ {
+		result, err := client.Get(&Hello{Name: "World"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if result.(*HelloResponse).Result != "Hello, World!" {
+			t.Errorf("Expected result 'Hello, World!', got '%s'", result.(*HelloResponse).Result)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestCacheIgnoresNonGetRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("Expected no If-None-Match header, got '%s'", r.Header.Get("If-None-Match"))
+		}
+		w.Header().Set("ETag", `"v1"`)
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.Cache = NewMemoryCache(time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Post(&Hello{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+}
+
+func TestMemoryCacheExpires(t *testing.T) {
+	cache := NewMemoryCache(10 * time.Millisecond)
+	cache.Set("key", &CacheEntry{ETag: `"v1"`})
+
+	if _, ok := cache.Get("key"); !ok {
+		t.Fatal("Expected entry to be cached")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := cache.Get("key"); ok {
+		t.Error("Expected entry to have expired")
+	}
+}
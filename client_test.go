@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/cookiejar"
 	"net/http/httptest"
+	"net/url"
+	"sync"
 	"testing"
 	"time"
 )
@@ -48,6 +51,27 @@ func TestSetHeader(t *testing.T) {
 	}
 }
 
+func TestSetHeaders(t *testing.T) {
+	client := NewClient("https://api.example.com")
+	client.SetHeader("X-Api-Key", "old")
+	client.SetHeader("X-Tenant", "acme")
+	client.SetHeaders(map[string]string{
+		"X-Api-Key":    "new",
+		"X-Request-By": "tests",
+	})
+
+	expected := map[string]string{
+		"X-Api-Key":    "new",
+		"X-Tenant":     "acme",
+		"X-Request-By": "tests",
+	}
+	for key, value := range expected {
+		if client.Headers[key] != value {
+			t.Errorf("Expected %s header to be '%s', got '%s'", key, value, client.Headers[key])
+		}
+	}
+}
+
 func TestGet(t *testing.T) {
 	// Create a test server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -276,6 +300,29 @@ func TestErrorResponse(t *testing.T) {
 	}
 }
 
+func TestClientSendsTypedRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json/reply/Hello" {
+			t.Errorf("Expected path '/json/reply/Hello', got '%s'", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, World!"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	var response HelloResponse
+
+	err := client.PostInto(&Hello{Name: "World"}, &response)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.Result != "Hello, World!" {
+		t.Errorf("Expected result 'Hello, World!', got '%s'", response.Result)
+	}
+}
+
 func TestContextCancellation(t *testing.T) {
 	// Create a test server with a delay
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -301,3 +348,92 @@ func TestContextCancellation(t *testing.T) {
 		t.Fatal("Expected an error due to context cancellation")
 	}
 }
+
+func TestClearHeaders(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+	client.SetHeader("X-Tenant", "acme")
+	client.SetBearerToken("token")
+
+	client.ClearHeaders()
+
+	if len(client.Headers) != 0 {
+		t.Errorf("Expected no headers, got %v", client.Headers)
+	}
+}
+
+func TestClearAuth(t *testing.T) {
+	var header http.Header
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetHeader("X-Tenant", "acme")
+	client.SetBearerToken("token")
+	client.RefreshToken = "refresh"
+	client.SetSessionId("session")
+	client.SetAuthSecret("secret")
+	client.SetTokenCookie(TokenCookie, "jwt")
+
+	client.ClearAuth()
+
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, name := range []string{"Authorization", "X-Ss-Id", "Authsecret", "Cookie"} {
+		if value := header.Get(name); value != "" {
+			t.Errorf("Expected no %s header, got '%s'", name, value)
+		}
+	}
+	if header.Get("X-Tenant") != "acme" {
+		t.Errorf("Expected X-Tenant header to be kept, got '%s'", header.Get("X-Tenant"))
+	}
+	if client.BearerToken != "" || client.RefreshToken != "" {
+		t.Errorf("Expected tokens to be cleared, got '%s' and '%s'", client.BearerToken, client.RefreshToken)
+	}
+}
+
+func TestClearAuthKeepsAssignedCookieJar(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+	jar, _ := cookiejar.New(nil)
+	client.HTTPClient.Jar = jar
+	client.SetTokenCookie(TokenCookie, "jwt")
+
+	client.ClearAuth()
+
+	if client.HTTPClient.Jar != jar {
+		t.Errorf("Expected the assigned cookie jar to be kept")
+	}
+	baseURL, _ := url.Parse(client.BaseURL)
+	if cookies := jar.Cookies(baseURL); len(cookies) != 1 {
+		t.Errorf("Expected the assigned cookie jar's cookies to be kept, got %v", cookies)
+	}
+}
+
+func TestClearAuthConcurrentRequests(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "ss-id", Value: "session"})
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			client.Get(&Hello{})
+		}()
+		go func() {
+			defer wg.Done()
+			client.ClearAuth()
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,39 @@
+package servicestack
+
+import (
+	"context"
+	"time"
+)
+
+// clock abstracts time so timing logic like retry backoff can be tested
+// without real sleeps
+type clock interface {
+	Now() time.Time
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// realClock is the clock backed by the time package
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// Sleep waits for the duration, returning the context's error if it's
+// cancelled first
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// getClock returns the client's clock, defaulting to the real clock
+func (c *JsonServiceClient) getClock() clock {
+	if c.clock == nil {
+		return realClock{}
+	}
+	return c.clock
+}
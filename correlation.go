@@ -0,0 +1,44 @@
+package servicestack
+
+import "net/http"
+
+// CorrelationIdHeader is the header servers echo a request's correlation id in
+const CorrelationIdHeader = "X-Correlation-Id"
+
+// Response is a response DTO along with the HTTP response details
+type Response struct {
+	Response   interface{}
+	StatusCode int
+	Header     http.Header
+	// CorrelationId is the X-Correlation-Id header, or the correlationId in
+	// the response's ResponseStatus Meta
+	CorrelationId string
+}
+
+// SendWithResponse sends the request DTO like Send, returning the response
+// along with its status code, headers and correlation id
+func (c *JsonServiceClient) SendWithResponse(method string, request interface{}, responseType interface{}) (*Response, error) {
+	responseType = responseInstance(responseType)
+	resp, err := c.send(c.defaultContext(), method, request, responseType)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{
+		Response:      responseType,
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header,
+		CorrelationId: correlationId(resp.Header, responseStatusOf(responseType)),
+	}, nil
+}
+
+// correlationId returns the correlation id of a response from its
+// X-Correlation-Id header, falling back to its ResponseStatus Meta
+func correlationId(header http.Header, status *ResponseStatus) string {
+	if id := header.Get(CorrelationIdHeader); id != "" {
+		return id
+	}
+	if status != nil {
+		return status.Meta["correlationId"]
+	}
+	return ""
+}
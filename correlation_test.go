@@ -0,0 +1,61 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSendWithResponseCorrelationIdHeader(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Correlation-Id", "abc-123")
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	response, err := client.SendWithResponse(http.MethodGet, &Hello{Name: "World"}, &HelloResponse{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.CorrelationId != "abc-123" {
+		t.Errorf("Expected correlation id 'abc-123', got '%s'", response.CorrelationId)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code 200, got %d", response.StatusCode)
+	}
+
+	if response.Response.(*HelloResponse).Result != "OK" {
+		t.Errorf("Expected result 'OK', got '%s'", response.Response.(*HelloResponse).Result)
+	}
+}
+
+func TestWebServiceExceptionCorrelationId(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ErrorResponse{ResponseStatus: &ResponseStatus{
+			ErrorCode: "ValidationException",
+			Message:   "Invalid request",
+			Meta:      map[string]string{"correlationId": "meta-456"},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	_, err := client.Get(&Hello{})
+
+	var webEx *WebServiceException
+	if !errors.As(err, &webEx) {
+		t.Fatalf("Expected a WebServiceException, got %v", err)
+	}
+
+	if webEx.CorrelationId != "meta-456" {
+		t.Errorf("Expected correlation id 'meta-456', got '%s'", webEx.CorrelationId)
+	}
+}
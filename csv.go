@@ -0,0 +1,157 @@
+package servicestack
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rawBody receives the undecoded response body
+type rawBody []byte
+
+// GetCSV sends the request DTO as a GET request for ServiceStack's CSV format
+// and decodes the rows into the slice of structs out points to, matching the
+// header row to the fields' Go or JSON names ignoring case
+func (c *JsonServiceClient) GetCSV(request IReturn, out interface{}) error {
+	ctx := withRequestHeader(c.defaultContext(), "Accept", "text/csv")
+	var body rawBody
+	if _, err := c.send(ctx, http.MethodGet, request, &body); err != nil {
+		return err
+	}
+	if err := decodeCSV(body, out); err != nil {
+		return fmt.Errorf("failed to decode CSV response: %w", err)
+	}
+	return nil
+}
+
+// decodeCSV decodes the CSV rows into the slice of structs or struct pointers
+// out points to
+func decodeCSV(data []byte, out interface{}) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("out must be a pointer to a slice, got %T", out)
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("out must be a slice of structs, got %T", out)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, max(len(records)-1, 0)))
+	if len(records) == 0 {
+		return nil
+	}
+
+	header := records[0]
+	for _, record := range records[1:] {
+		item := reflect.New(structType).Elem()
+		for i, name := range header {
+			if i >= len(record) {
+				break
+			}
+			field, ok := csvField(item, strings.TrimSpace(name))
+			if !ok {
+				continue
+			}
+			if err := setCSVValue(field, record[i]); err != nil {
+				return fmt.Errorf("invalid value %q for column %s: %w", record[i], name, err)
+			}
+		}
+		if elemType.Kind() == reflect.Ptr {
+			item = item.Addr()
+		}
+		slice.Set(reflect.Append(slice, item))
+	}
+	return nil
+}
+
+// csvField finds the struct field for the CSV column by its Go or JSON name,
+// ignoring case
+func csvField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || hasTagOption(field, "ignore") {
+			continue
+		}
+		jsonName, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+		if strings.EqualFold(field.Name, name) || strings.EqualFold(jsonName, name) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// setCSVValue parses the CSV value into the field, leaving empty values as
+// the field's zero value
+func setCSVValue(field reflect.Value, value string) error {
+	if value == "" {
+		return nil
+	}
+	if field.Kind() == reflect.Ptr {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return unmarshaler.UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Type() == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			field.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return errors.New("unsupported field type " + field.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package servicestack
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type SalesReport struct {
+	Month string `json:"month"`
+}
+
+func (r *SalesReport) ResponseType() interface{} { return &[]SalesRow{} }
+
+type SalesRow struct {
+	Region  string   `json:"region"`
+	Notes   string   `json:"notes"`
+	Units   int      `json:"units"`
+	Revenue float64  `json:"revenue"`
+	Active  bool     `json:"active"`
+	Target  *float64 `json:"target,omitempty"`
+}
+
+func TestJsonServiceClientGetCSV(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/csv" {
+			t.Errorf("Expected Accept 'text/csv', got '%s'", r.Header.Get("Accept"))
+		}
+		if r.URL.Query().Get("month") != "2024-01" {
+			t.Errorf("Expected month '2024-01', got '%s'", r.URL.Query().Get("month"))
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		io.WriteString(w, "Region,Notes,Units,Revenue,Active,Target\n"+
+			"North,\"Strong, steady growth\",120,1500.5,true,1400\n"+
+			"\"South, East\",\"Said \"\"hold\"\"\",80,990.25,false,\n")
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	var rows []SalesRow
+	if err := client.GetCSV(&SalesReport{Month: "2024-01"}, &rows); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(rows))
+	}
+
+	if rows[0].Region != "North" || rows[0].Notes != "Strong, steady growth" || rows[0].Units != 120 || rows[0].Revenue != 1500.5 || !rows[0].Active {
+		t.Errorf("Unexpected first row: %+v", rows[0])
+	}
+	if rows[0].Target == nil || *rows[0].Target != 1400 {
+		t.Errorf("Expected target 1400, got %v", rows[0].Target)
+	}
+
+	if rows[1].Region != "South, East" || rows[1].Notes != `Said "hold"` || rows[1].Active {
+		t.Errorf("Unexpected second row: %+v", rows[1])
+	}
+	if rows[1].Target != nil {
+		t.Errorf("Expected no target for an empty value, got %v", *rows[1].Target)
+	}
+}
+
+func TestDecodeCSVInvalidValue(t *testing.T) {
+	var rows []*SalesRow
+	if err := decodeCSV([]byte("Units\nmany\n"), &rows); err == nil {
+		t.Error("Expected an error for an invalid int value")
+	}
+
+	if err := decodeCSV([]byte("Units\n5\n"), &rows); err != nil || len(rows) != 1 || rows[0].Units != 5 {
+		t.Errorf("Expected a single row with 5 units, got %v (%v)", rows, err)
+	}
+}
+
+func TestJsonServiceClientGetCSVCachedSeparately(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if r.Header.Get("Accept") == "text/csv" {
+			w.Header().Set("ETag", `"csv"`)
+			w.Header().Set("Content-Type", "text/csv")
+			io.WriteString(w, "Region,Units\nNorth,120\n")
+			return
+		}
+		w.Header().Set("ETag", `"json"`)
+		io.WriteString(w, `[{"region":"North","units":120}]`)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.Cache = NewMemoryCache(time.Minute)
+
+	var rows []SalesRow
+	if err := client.GetCSV(&SalesReport{}, &rows); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := client.Get(&SalesReport{})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if rows := *result.(*[]SalesRow); len(rows) != 1 || rows[0].Units != 120 {
+			t.Errorf("Expected the JSON response, got %v", rows)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package servicestack
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// redactedHeaders are the headers whose values DumpRequest hides
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Authsecret":    true,
+	"X-Ss-Id":       true,
+}
+
+// DumpRequest returns the method, URL, headers and body the request DTO
+// would be sent with, without sending it, e.g. to troubleshoot routing and
+// serialization. Credential headers, the authsecret query param and fields
+// tagged `servicestack:"secret"` are redacted.
+func (c *JsonServiceClient) DumpRequest(method string, request interface{}) (string, error) {
+	req, body, data, err := c.buildRequest(method, request)
+	if err != nil {
+		return "", err
+	}
+
+	var dump strings.Builder
+	fmt.Fprintf(&dump, "%s %s\n", req.Method, redactAuthSecret(req.URL.String()))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := strings.Join(req.Header[name], ", ")
+		if redactedHeaders[name] {
+			value = "[REDACTED]"
+		}
+		fmt.Fprintf(&dump, "%s: %s\n", name, value)
+	}
+
+	if data != nil {
+		fmt.Fprintf(&dump, "\n%s\n", c.redactedBody(body, data))
+	}
+	return dump.String(), nil
+}
+
+// EffectiveHeaders returns the headers the request DTO would be sent with,
+// including the client's headers, the defaults and credentials, without
+// sending it, e.g. to troubleshoot authentication. Cookies from the cookie jar
+// aren't included. It returns nil if the request can't be built.
+func (c *JsonServiceClient) EffectiveHeaders(request interface{}) http.Header {
+	req, _, _, err := c.buildRequest(httpMethodOf(request, http.MethodPost), request)
+	if err != nil {
+		return nil
+	}
+	return req.Header
+}
+
+// buildRequest creates the HTTP request the request DTO would be sent with,
+// returning the request's body and its marshaled JSON
+func (c *JsonServiceClient) buildRequest(method string, request interface{}) (*http.Request, interface{}, []byte, error) {
+	ctx, method, path, body := c.prepareRequest(c.defaultContext(), method, request)
+
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = c.marshalRequest(body)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	req, err := c.newRequest(ctx, method, c.requestURL(overriddenMethod(ctx, method), path), data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return req, body, data, nil
+}
@@ -0,0 +1,113 @@
+package servicestack
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestJsonServiceClientDumpRequest(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+	client.SetBearerToken("secret-token")
+	client.SetAuthSecret("admin-secret")
+	client.SetSessionId("session-id")
+	client.SetHeader("X-Tenant", "acme")
+
+	dump, err := client.DumpRequest(http.MethodPost, &Hello{Name: "World"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"POST https://api.example.com/json/reply/Hello\n",
+		"Authorization: [REDACTED]\n",
+		"Authsecret: [REDACTED]\n",
+		"Content-Type: application/json\n",
+		"X-Ss-Id: [REDACTED]\n",
+		"X-Tenant: acme\n",
+		"\n{\"name\":\"World\"}\n",
+	}
+	for _, part := range expected {
+		if !strings.Contains(dump, part) {
+			t.Errorf("Expected dump to contain %q, got:\n%s", part, dump)
+		}
+	}
+	for _, secret := range []string{"secret-token", "admin-secret", "session-id"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("Expected %q to be redacted, got:\n%s", secret, dump)
+		}
+	}
+}
+
+func TestJsonServiceClientDumpRequestRedactsSecrets(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+	client.SetAuthSecret("admin-secret")
+	client.AuthSecretInQuery = true
+
+	dump, err := client.DumpRequest(http.MethodPost, &CreateAccount{UserName: "jane", Password: "p@ssw0rd"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"POST https://api.example.com/json/reply/CreateAccount?authsecret=[REDACTED]\n",
+		"\n{\"userName\":\"jane\",\"password\":\"[REDACTED]\"}\n",
+	}
+	for _, part := range expected {
+		if !strings.Contains(dump, part) {
+			t.Errorf("Expected dump to contain %q, got:\n%s", part, dump)
+		}
+	}
+	for _, secret := range []string{"admin-secret", "p@ssw0rd"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("Expected %q to be redacted, got:\n%s", secret, dump)
+		}
+	}
+}
+
+func TestJsonServiceClientDumpGetRequest(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+
+	dump, err := client.DumpRequest(http.MethodGet, &Hello{Name: "World"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "GET https://api.example.com/json/reply/Hello?name=World\nAccept: application/json\n"
+	if dump != expected {
+		t.Errorf("Expected dump %q, got %q", expected, dump)
+	}
+}
+
+func TestJsonServiceClientEffectiveHeaders(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+	client.SetBearerToken("secret-token")
+	client.SetHeader("X-Tenant", "acme")
+
+	headers := client.EffectiveHeaders(&Hello{Name: "World"})
+	if headers == nil {
+		t.Fatal("Expected headers to be returned")
+	}
+
+	if headers.Get("Authorization") != "Bearer secret-token" {
+		t.Errorf("Expected Authorization 'Bearer secret-token', got '%s'", headers.Get("Authorization"))
+	}
+	if headers.Get("Content-Type") != "application/json" {
+		t.Errorf("Expected Content-Type 'application/json', got '%s'", headers.Get("Content-Type"))
+	}
+	if headers.Get("X-Tenant") != "acme" {
+		t.Errorf("Expected X-Tenant 'acme', got '%s'", headers.Get("X-Tenant"))
+	}
+}
+
+func TestJsonServiceClientEffectiveHeadersForGet(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+
+	headers := client.EffectiveHeaders(&GetHello{Name: "World"})
+	if _, ok := headers["Content-Type"]; ok {
+		t.Errorf("Expected no Content-Type for a GET request, got '%s'", headers.Get("Content-Type"))
+	}
+	if headers.Get("Accept") != "application/json" {
+		t.Errorf("Expected Accept 'application/json', got '%s'", headers.Get("Accept"))
+	}
+}
@@ -0,0 +1,56 @@
+package servicestack
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Enum is implemented by enum types to serialize as their ServiceStack enum
+// name rather than their underlying value
+type Enum interface {
+	EnumString() string
+}
+
+var (
+	enumType     = reflect.TypeOf((*Enum)(nil)).Elem()
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+	// nonEnumTypes are integer types implementing fmt.Stringer that aren't
+	// enums, which keep being sent as their numeric values
+	nonEnumTypes = map[reflect.Type]bool{
+		reflect.TypeOf(time.Duration(0)): true,
+		reflect.TypeOf(time.Month(0)):    true,
+		reflect.TypeOf(time.Weekday(0)):  true,
+	}
+)
+
+// enumString returns the name of an enum value, either from its Enum
+// implementation or from fmt.Stringer on integer types, e.g.
+//
+//	type Status int
+//	func (s Status) String() string { ... }
+//
+// The standard library's time.Duration, time.Month and time.Weekday aren't
+// treated as enums.
+func enumString(v reflect.Value) (string, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return "", false
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return "", false
+	}
+
+	if v.Type().Implements(enumType) {
+		return v.Interface().(Enum).EnumString(), true
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Type().Implements(stringerType) && !nonEnumTypes[v.Type()] {
+			return v.Interface().(fmt.Stringer).String(), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,126 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Status int
+
+const (
+	StatusPending Status = iota
+	StatusActive
+	StatusClosed
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusActive:
+		return "Active"
+	case StatusClosed:
+		return "Closed"
+	}
+	return "Pending"
+}
+
+type Color int
+
+func (c Color) EnumString() string {
+	return []string{"Red", "Green", "Blue"}[c]
+}
+
+type UpdateStatus struct {
+	Id       int      `json:"id"`
+	Status   Status   `json:"status"`
+	Color    Color    `json:"color"`
+	Statuses []Status `json:"statuses,omitempty"`
+}
+
+func (r *UpdateStatus) ResponseType() interface{} {
+	return &HelloResponse{}
+}
+
+func TestEnumSerializedInBody(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	request := &UpdateStatus{Id: 1, Status: StatusActive, Color: 2, Statuses: []Status{StatusActive, StatusClosed}}
+	if _, err := client.Post(request); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if body["status"] != "Active" {
+		t.Errorf("Expected status 'Active', got %v", body["status"])
+	}
+
+	if body["color"] != "Blue" {
+		t.Errorf("Expected color 'Blue', got %v", body["color"])
+	}
+
+	statuses, _ := body["statuses"].([]interface{})
+	if len(statuses) != 2 || statuses[1] != "Closed" {
+		t.Errorf("Expected statuses [Active Closed], got %v", body["statuses"])
+	}
+}
+
+func TestEnumSerializedInQuery(t *testing.T) {
+	var query map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	request := &UpdateStatus{Id: 1, Status: StatusActive, Color: 1, Statuses: []Status{StatusActive, StatusClosed}}
+	if _, err := client.Get(request); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if query["status"][0] != "Active" {
+		t.Errorf("Expected status 'Active', got '%s'", query["status"][0])
+	}
+
+	if query["color"][0] != "Green" {
+		t.Errorf("Expected color 'Green', got '%s'", query["color"][0])
+	}
+
+	if query["statuses"][0] != "Active,Closed" {
+		t.Errorf("Expected statuses 'Active,Closed', got '%s'", query["statuses"][0])
+	}
+}
+
+type ScheduleJob struct {
+	Timeout time.Duration `json:"timeout"`
+	Month   time.Month    `json:"month"`
+	Status  Status        `json:"status"`
+}
+
+func TestDurationNotSerializedAsEnum(t *testing.T) {
+	request := &ScheduleJob{Timeout: time.Second, Month: time.March, Status: StatusActive}
+
+	data, err := MarshalRequest(request)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := `{"timeout":1000000000,"month":3,"status":"Active"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	query := toQueryString(request)
+	for _, param := range []string{"timeout=1000000000", "month=3", "status=Active"} {
+		if !strings.Contains(query, param) {
+			t.Errorf("Expected query '%s' to contain '%s'", query, param)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package servicestack
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by NewJsonServiceClientFromEnv
+const (
+	EnvBaseURL     = "SERVICESTACK_BASE_URL"
+	EnvBearerToken = "SERVICESTACK_BEARER_TOKEN"
+	EnvUserName    = "SERVICESTACK_USERNAME"
+	EnvPassword    = "SERVICESTACK_PASSWORD"
+	EnvAuthSecret  = "SERVICESTACK_AUTH_SECRET"
+	EnvTimeout     = "SERVICESTACK_TIMEOUT"
+)
+
+// NewJsonServiceClientFromEnv creates a JsonServiceClient configured from
+// environment variables:
+//
+//	SERVICESTACK_BASE_URL      base URL of the service (required)
+//	SERVICESTACK_BEARER_TOKEN  bearer token for authentication
+//	SERVICESTACK_USERNAME      username for Basic authentication
+//	SERVICESTACK_PASSWORD      password for Basic authentication
+//	SERVICESTACK_AUTH_SECRET   AuthSecret for admin access
+//	SERVICESTACK_TIMEOUT       request timeout, e.g. "30s" or a number of seconds
+func NewJsonServiceClientFromEnv() (*JsonServiceClient, error) {
+	baseURL := os.Getenv(EnvBaseURL)
+	if baseURL == "" {
+		return nil, fmt.Errorf("%s environment variable is required", EnvBaseURL)
+	}
+
+	client := NewJsonServiceClient(baseURL)
+
+	if token := os.Getenv(EnvBearerToken); token != "" {
+		client.SetBearerToken(token)
+	} else if userName := os.Getenv(EnvUserName); userName != "" {
+		client.SetCredentials(userName, os.Getenv(EnvPassword))
+	}
+
+	if secret := os.Getenv(EnvAuthSecret); secret != "" {
+		client.SetAuthSecret(secret)
+	}
+
+	if value := os.Getenv(EnvTimeout); value != "" {
+		timeout, err := parseTimeout(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvTimeout, err)
+		}
+		client.SetTimeout(timeout)
+	}
+
+	return client, nil
+}
+
+// parseTimeout parses a duration string or a whole number of seconds
+func parseTimeout(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	return time.ParseDuration(value)
+}
@@ -0,0 +1,68 @@
+package servicestack
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewJsonServiceClientFromEnv(t *testing.T) {
+	t.Setenv(EnvBaseURL, "https://api.example.com")
+	t.Setenv(EnvBearerToken, "token123")
+	t.Setenv(EnvAuthSecret, "secret")
+	t.Setenv(EnvTimeout, "45s")
+
+	client, err := NewJsonServiceClientFromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if client.BaseURL != "https://api.example.com" {
+		t.Errorf("Expected BaseURL 'https://api.example.com', got '%s'", client.BaseURL)
+	}
+
+	if client.Headers["Authorization"] != "Bearer token123" {
+		t.Errorf("Expected Authorization 'Bearer token123', got '%s'", client.Headers["Authorization"])
+	}
+
+	if client.AuthSecret != "secret" {
+		t.Errorf("Expected AuthSecret 'secret', got '%s'", client.AuthSecret)
+	}
+
+	if client.HTTPClient.Timeout != 45*time.Second {
+		t.Errorf("Expected timeout 45s, got %v", client.HTTPClient.Timeout)
+	}
+}
+
+func TestNewJsonServiceClientFromEnvCredentials(t *testing.T) {
+	t.Setenv(EnvBaseURL, "https://api.example.com")
+	t.Setenv(EnvBearerToken, "")
+	t.Setenv(EnvUserName, "user")
+	t.Setenv(EnvPassword, "pass")
+	t.Setenv(EnvTimeout, "10")
+
+	client, err := NewJsonServiceClientFromEnv()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if client.Headers["Authorization"] != "Basic dXNlcjpwYXNz" {
+		t.Errorf("Expected Authorization 'Basic dXNlcjpwYXNz', got '%s'", client.Headers["Authorization"])
+	}
+
+	if client.HTTPClient.Timeout != 10*time.Second {
+		t.Errorf("Expected timeout 10s, got %v", client.HTTPClient.Timeout)
+	}
+}
+
+func TestNewJsonServiceClientFromEnvErrors(t *testing.T) {
+	t.Setenv(EnvBaseURL, "")
+	if _, err := NewJsonServiceClientFromEnv(); err == nil {
+		t.Error("Expected an error when the base URL is missing")
+	}
+
+	t.Setenv(EnvBaseURL, "https://api.example.com")
+	t.Setenv(EnvTimeout, "later")
+	if _, err := NewJsonServiceClientFromEnv(); err == nil {
+		t.Error("Expected an error for an invalid timeout")
+	}
+}
@@ -20,6 +20,20 @@ func (r *HelloRequest) ResponseType() interface{} {
 	return &HelloResponse{}
 }
 
+// GetHelloRequest is a sample request DTO for a GET-only service
+type GetHelloRequest struct {
+	Name string `json:"name"`
+}
+
+func (r *GetHelloRequest) ResponseType() interface{} {
+	return &HelloResponse{}
+}
+
+// HttpMethod sends GetHelloRequest as a GET request
+func (r *GetHelloRequest) HttpMethod() string {
+	return "GET"
+}
+
 // HelloResponse is a sample response DTO
 type HelloResponse struct {
 	Result string `json:"result"`
@@ -36,6 +50,11 @@ func (r *AuthenticateRequest) ResponseType() interface{} {
 	return &AuthenticateResponse{}
 }
 
+// HttpMethod sends AuthenticateRequest as a POST request
+func (r *AuthenticateRequest) HttpMethod() string {
+	return "POST"
+}
+
 // AuthenticateResponse contains authentication result
 type AuthenticateResponse struct {
 	SessionId      string                      `json:"sessionId"`
@@ -103,8 +122,19 @@ func main() {
 	fmt.Println("Basic auth credentials set")
 	fmt.Println()
 
-	// Example 5: Error handling
-	fmt.Println("Example 5: Error Handling")
+	// Example 5: Dispatch picks GET or POST from the value the DTO's
+	// HttpMethod returns
+	fmt.Println("Example 5: Marker-based dispatch")
+	result, err = client.SendRequest(&GetHelloRequest{Name: "Markers"})
+	if err != nil {
+		log.Printf("Error: %v\n", err)
+	} else {
+		response := result.(*HelloResponse)
+		fmt.Printf("Dispatched Response: %s\n\n", response.Result)
+	}
+
+	// Example 6: Error handling
+	fmt.Println("Example 6: Error Handling")
 	invalidRequest := &HelloRequest{Name: ""} // Assuming empty name might cause validation error
 	_, err = client.Post(invalidRequest)
 	if err != nil {
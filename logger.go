@@ -0,0 +1,80 @@
+package servicestack
+
+import (
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Logger is the logger the client writes warnings and debug output to,
+// implemented by *log.Logger
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// logf writes to the client's Logger, or the standard logger if it isn't set
+func (c *JsonServiceClient) logf(format string, v ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, v...)
+		return
+	}
+	log.Printf(format, v...)
+}
+
+// loggedBody returns the body for debug logging, truncated to
+// MaxLoggedBodyBytes
+func (c *JsonServiceClient) loggedBody(body []byte) string {
+	maxBytes := c.MaxLoggedBodyBytes
+	if maxBytes <= 0 {
+		maxBytes = 1024
+	}
+	if len(body) > maxBytes {
+		return string(body[:maxBytes]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+// credentialFields matches the credentials returned in response bodies, like
+// the tokens and session id of an AuthenticateResponse
+var credentialFields = regexp.MustCompile(`(?i)("(?:bearerToken|refreshToken|accessToken|sessionId)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// loggedResponse returns the response body for debug logging with the
+// credentials it returns redacted
+func (c *JsonServiceClient) loggedResponse(body []byte) string {
+	return c.loggedBody(credentialFields.ReplaceAll(body, []byte(`${1}"[REDACTED]"`)))
+}
+
+// loggedRequest returns the request URL and body for debug logging with the
+// authsecret query param and fields tagged `servicestack:"secret"` redacted
+func (c *JsonServiceClient) loggedRequest(requestURL string, request interface{}, body []byte) (string, string) {
+	return redactAuthSecret(requestURL), c.loggedBody(c.redactedBody(request, body))
+}
+
+// redactedBody returns the request's JSON body with fields tagged
+// `servicestack:"secret"` redacted, or body if it can't be marshaled
+func (c *JsonServiceClient) redactedBody(request interface{}, body []byte) []byte {
+	if request != nil {
+		if redacted, err := marshalRedacted(request, c.UseCamelCaseNames); err == nil {
+			return redacted
+		}
+	}
+	return body
+}
+
+// redactAuthSecret replaces the value of the URL's authsecret query param
+func redactAuthSecret(requestURL string) string {
+	u, err := url.Parse(requestURL)
+	if err != nil || u.RawQuery == "" {
+		return requestURL
+	}
+
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		if name, _, _ := strings.Cut(param, "="); strings.EqualFold(name, "authsecret") {
+			params[i] = name + "=[REDACTED]"
+		}
+	}
+	u.RawQuery = strings.Join(params, "&")
+	return u.String()
+}
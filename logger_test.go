@@ -0,0 +1,114 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugLoggingTruncatesLargeBodies(t *testing.T) {
+	large := strings.Repeat("a", 5000)
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HelloResponse{Result: large})
+	}))
+	defer server.Close()
+
+	logger := &testLogger{}
+	client := NewJsonServiceClient(server.URL)
+	client.Logger = logger
+	client.Debug = true
+	client.MaxLoggedBodyBytes = 100
+
+	if _, err := client.Post(&Hello{Name: large}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(logger.messages) != 2 {
+		t.Fatalf("Expected 2 log messages, got %d", len(logger.messages))
+	}
+
+	for _, message := range logger.messages {
+		if !strings.HasSuffix(message, "...(truncated)") {
+			t.Errorf("Expected message to be truncated, got '%s'", message)
+		}
+		if len(message) > 300 {
+			t.Errorf("Expected truncated message, got %d bytes", len(message))
+		}
+	}
+}
+
+func TestDebugLoggingKeepsSmallBodies(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+
+	if body := client.loggedBody([]byte(`{"name":"World"}`)); body != `{"name":"World"}` {
+		t.Errorf("Expected body to be logged in full, got '%s'", body)
+	}
+}
+
+func TestDebugLoggingRedactsSecrets(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(AuthenticateResponse{UserName: "admin"})
+	}))
+	defer server.Close()
+
+	logger := &testLogger{}
+	client := NewJsonServiceClient(server.URL)
+	client.Logger = logger
+	client.Debug = true
+	client.AuthSecretInQuery = true
+	client.SetAuthSecret("ADMINSECRET")
+
+	if err := client.Login("admin", "hunter2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	request := logger.messages[0]
+	for _, secret := range []string{"ADMINSECRET", "hunter2"} {
+		if strings.Contains(request, secret) {
+			t.Errorf("Expected %q to be redacted, got '%s'", secret, request)
+		}
+	}
+	for _, part := range []string{"authsecret=[REDACTED]", `"password":"[REDACTED]"`, `"userName":"admin"`} {
+		if !strings.Contains(request, part) {
+			t.Errorf("Expected log to contain %q, got '%s'", part, request)
+		}
+	}
+}
+
+func TestDebugLoggingRedactsResponseTokens(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(AuthenticateResponse{
+			UserName:     "admin",
+			SessionId:    "SESSION-ID",
+			BearerToken:  "BEARER-TOKEN",
+			RefreshToken: "REFRESH-TOKEN",
+		})
+	}))
+	defer server.Close()
+
+	logger := &testLogger{}
+	client := NewJsonServiceClient(server.URL)
+	client.Logger = logger
+	client.Debug = true
+
+	if err := client.Login("admin", "hunter2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response := logger.messages[1]
+	for _, secret := range []string{"SESSION-ID", "BEARER-TOKEN", "REFRESH-TOKEN"} {
+		if strings.Contains(response, secret) {
+			t.Errorf("Expected %q to be redacted, got '%s'", secret, response)
+		}
+	}
+	for _, part := range []string{`"bearerToken":"[REDACTED]"`, `"userName":"admin"`} {
+		if !strings.Contains(response, part) {
+			t.Errorf("Expected log to contain %q, got '%s'", part, response)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package servicestack
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// MarshalRequest returns the JSON body the client sends for the request DTO
+// with its default options, omitting fields tagged `servicestack:"ignore"`
+// and fields substituted into its IRoute route, and emitting enum values as
+// their names, e.g. for snapshot testing DTOs. Routes registered with
+// RegisterRoute are per client, so their fields aren't omitted.
+func MarshalRequest(request interface{}) ([]byte, error) {
+	var omit map[string]bool
+	if route, ok := request.(IRoute); ok && route.Route() != "" {
+		_, omit = substituteRoute(route.Route(), request)
+	}
+	return marshalJSONOmitting(request, false, omit)
+}
+
+// marshalJSON marshals the value to JSON like json.Marshal, except enum
+// values are emitted as their string names and, when camelCase is set,
+// exported struct fields without an explicit json name are emitted in
+// camelCase, matching ServiceStack's default JSON naming.
+//
+// Limitations: types implementing json.Marshaler or encoding.TextMarshaler
+// are marshalled as-is, the ",string" tag option is ignored and map keys are
+// sorted by their string form.
+func marshalJSON(value interface{}, camelCase bool) ([]byte, error) {
+	return marshalJSONOmitting(value, camelCase, nil)
+}
+
+// marshalJSONOmitting marshals the value like marshalJSON, omitting the named
+// fields of the top-level struct, e.g. fields sent in the route's path
+func marshalJSONOmitting(value interface{}, camelCase bool, omit map[string]bool) ([]byte, error) {
+	var buf bytes.Buffer
+	w := jsonWriter{buf: &buf, camelCase: camelCase, omit: omit}
+	if err := w.write(reflect.ValueOf(value)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// marshalRedacted marshals the value like marshalJSON, replacing the values
+// of fields tagged `servicestack:"secret"` with [REDACTED]
+func marshalRedacted(value interface{}, camelCase bool) ([]byte, error) {
+	var omit map[string]bool
+	if partial, ok := value.(*partialRequest); ok {
+		value, omit = partial.request, partial.omit
+	}
+	var buf bytes.Buffer
+	w := jsonWriter{buf: &buf, camelCase: camelCase, omit: omit, redact: true}
+	if err := w.write(reflect.ValueOf(value)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// partialRequest is a request DTO sent without the omitted fields in its
+// body and query string, e.g. fields substituted into its route
+type partialRequest struct {
+	request interface{}
+	omit    map[string]bool
+}
+
+// jsonWriter writes reflected values as JSON
+type jsonWriter struct {
+	buf       *bytes.Buffer
+	camelCase bool
+	// omit are the names of the fields of the current struct that are skipped
+	omit map[string]bool
+	// redact hides the values of fields tagged `servicestack:"secret"`
+	redact bool
+}
+
+// write writes the JSON for v
+func (w jsonWriter) write(v reflect.Value) error {
+	buf := w.buf
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSON(buf, v.Interface())
+	}
+
+	if name, ok := enumString(v); ok {
+		return writeJSON(buf, name)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return w.write(v.Elem())
+
+	case reflect.Struct:
+		buf.WriteByte('{')
+		first := true
+		if err := w.writeFields(v, &first); err != nil {
+			return err
+		}
+		buf.WriteByte('}')
+		return nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		// Byte slices are base64 encoded
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return writeJSON(buf, v.Interface())
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := w.write(v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		buf.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSON(buf, fmt.Sprint(key.Interface())); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			if err := w.write(v.MapIndex(key)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	}
+
+	return writeJSON(buf, v.Interface())
+}
+
+// writeFields writes the struct's fields, flattening untagged embedded structs
+func (w jsonWriter) writeFields(v reflect.Value, first *bool) error {
+	buf := w.buf
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || hasTagOption(field, "ignore") {
+			continue
+		}
+
+		if field.Anonymous && tag == "" {
+			embedded := fieldValue
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := w.writeFields(embedded, first); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() || w.omit[field.Name] {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+			if w.camelCase {
+				name = camelCase(field.Name)
+			}
+		}
+		if strings.Contains(opts, "omitempty") && isEmptyValue(fieldValue) {
+			continue
+		}
+
+		if !*first {
+			buf.WriteByte(',')
+		}
+		*first = false
+
+		if err := writeJSON(buf, name); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if w.redact && hasTagOption(field, "secret") {
+			buf.WriteString(`"[REDACTED]"`)
+			continue
+		}
+		nested := w
+		nested.omit = nil
+		if err := nested.write(fieldValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasTagOption reports whether the field's servicestack tag has the option,
+// e.g. `servicestack:"ignore"` for client-only fields that aren't sent
+func hasTagOption(field reflect.StructField, option string) bool {
+	for _, opt := range strings.Split(field.Tag.Get("servicestack"), ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSON writes the standard JSON encoding of value into buf
+func writeJSON(buf *bytes.Buffer, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// isEmptyValue reports whether v is empty per encoding/json's omitempty rules
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct:
+		return false
+	}
+	return v.IsZero()
+}
+
+// camelCase lowercases the leading uppercase run of a Go field name,
+// e.g. Name -> name, ID -> id and HTTPServer -> httpServer
+func camelCase(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
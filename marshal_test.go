@@ -0,0 +1,263 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type Address struct {
+	Street   string
+	PostCode string `json:"zip"`
+}
+
+type Audit struct {
+	CreatedBy string
+}
+
+type CreateCustomer struct {
+	Audit
+	ID        int
+	FirstName string
+	LastName  string `json:"surname"`
+	Email     string `json:",omitempty"`
+	Internal  string `json:"-"`
+	Address   *Address
+	Tags      []string
+	Created   time.Time
+}
+
+func TestMarshalJSON(t *testing.T) {
+	request := CreateCustomer{
+		Audit:     Audit{CreatedBy: "admin"},
+		ID:        1,
+		FirstName: "John",
+		LastName:  "Smith",
+		Internal:  "secret",
+		Address:   &Address{Street: "1 Main St", PostCode: "12345"},
+		Tags:      []string{"vip"},
+		Created:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	tagged, err := marshalJSON(request, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stdlib, _ := json.Marshal(request)
+	if string(tagged) != string(stdlib) {
+		t.Errorf("Expected %s to match encoding/json output %s", tagged, stdlib)
+	}
+
+	expectedTagged := `{"CreatedBy":"admin","ID":1,"FirstName":"John","surname":"Smith",` +
+		`"Address":{"Street":"1 Main St","zip":"12345"},"Tags":["vip"],"Created":"2024-01-02T03:04:05Z"}`
+	if string(tagged) != expectedTagged {
+		t.Errorf("Expected %s, got %s", expectedTagged, tagged)
+	}
+
+	camelCased, err := marshalJSON(request, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedCamelCased := `{"createdBy":"admin","id":1,"firstName":"John","surname":"Smith",` +
+		`"address":{"street":"1 Main St","zip":"12345"},"tags":["vip"],"created":"2024-01-02T03:04:05Z"}`
+	if string(camelCased) != expectedCamelCased {
+		t.Errorf("Expected %s, got %s", expectedCamelCased, camelCased)
+	}
+}
+
+func TestCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"Name":       "name",
+		"ID":         "id",
+		"UserID":     "userID",
+		"HTTPServer": "httpServer",
+		"name":       "name",
+	}
+
+	for name, expected := range tests {
+		if actual := camelCase(name); actual != expected {
+			t.Errorf("Expected camelCase(%q) to be '%s', got '%s'", name, expected, actual)
+		}
+	}
+}
+
+func TestJsonServiceClientUseCamelCaseNames(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.UseCamelCaseNames = true
+
+	if _, err := client.Send(http.MethodPost, &CreateCustomer{FirstName: "John"}, &HelloResponse{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if body["firstName"] != "John" {
+		t.Errorf("Expected firstName 'John', got %v", body["firstName"])
+	}
+
+	if _, ok := body["FirstName"]; ok {
+		t.Error("Expected FirstName not to be sent")
+	}
+}
+
+type SaveDraft struct {
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	Selected bool   `json:"selected" servicestack:"ignore"`
+	Scroll   int    `servicestack:"ignore"`
+}
+
+func TestIgnoredFieldsAreNotSent(t *testing.T) {
+	var bodies, queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.IncludeQueryOnPost = true
+	request := &SaveDraft{Title: "Draft", Body: "Text", Selected: true, Scroll: 120}
+
+	if _, err := client.Send(http.MethodPost, request, &map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Send(http.MethodGet, request, &map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if bodies[0] != `{"title":"Draft","body":"Text"}` {
+		t.Errorf("Expected body without ignored fields, got '%s'", bodies[0])
+	}
+	for _, query := range queries {
+		if query != "body=Text&title=Draft" {
+			t.Errorf("Expected query without ignored fields, got '%s'", query)
+		}
+	}
+}
+
+type PatchCustomer struct {
+	Id      int      `json:"id"`
+	Name    string   `json:"name"`
+	Email   string   `json:"email"`
+	Age     int      `json:"age"`
+	Address *Address `json:"address"`
+}
+
+func (r *PatchCustomer) ResponseType() interface{} {
+	return &HelloResponse{}
+}
+
+func TestJsonServiceClientPatchPartial(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("Expected PATCH method, got %s", r.Method)
+		}
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Patched"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result, err := client.PatchPartial(&PatchCustomer{Id: 1, Email: "jane@example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.(*HelloResponse).Result != "Patched" {
+		t.Errorf("Expected result 'Patched', got '%s'", result.(*HelloResponse).Result)
+	}
+	if body != `{"id":1,"email":"jane@example.com"}` {
+		t.Errorf("Expected only set fields in the body, got '%s'", body)
+	}
+}
+
+type SaveTicket struct {
+	Title    string `json:"title"`
+	Status   Status `json:"status"`
+	Color    Color  `json:"color,omitempty"`
+	Selected bool   `json:"selected" servicestack:"ignore"`
+	Notes    string `json:"-"`
+}
+
+func TestMarshalRequest(t *testing.T) {
+	var sent string
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	request := &SaveTicket{Title: "Crash", Status: StatusActive, Color: 2, Selected: true, Notes: "internal"}
+	data, err := MarshalRequest(request)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `{"title":"Crash","status":"Active","color":"Blue"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.Send(http.MethodPost, request, &map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent != string(data) {
+		t.Errorf("Expected the sent body %s to match %s", sent, data)
+	}
+}
+
+type SaveItem struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (r *SaveItem) ResponseType() interface{} { return &HelloResponse{} }
+func (r *SaveItem) Route() string             { return "/items/{Id}" }
+
+func TestMarshalRequestOmitsRouteFields(t *testing.T) {
+	var sent string
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sent = string(body)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	request := &SaveItem{Id: 1, Name: "a"}
+	data, err := MarshalRequest(request)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `{"name":"a"}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.Post(request); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent != string(data) {
+		t.Errorf("Expected the sent body %s to match %s", sent, data)
+	}
+}
@@ -0,0 +1,84 @@
+package servicestack
+
+import (
+	"net/http"
+)
+
+// AppMetadata is the app info returned by ServiceStack's /metadata/app endpoint
+type AppMetadata struct {
+	App     AppInfo           `json:"app"`
+	Plugins PluginInfo        `json:"plugins"`
+	Meta    map[string]string `json:"meta,omitempty"`
+}
+
+// AppInfo describes the ServiceStack App
+type AppInfo struct {
+	BaseUrl             string            `json:"baseUrl,omitempty"`
+	ServiceStackVersion string            `json:"serviceStackVersion,omitempty"`
+	ServiceName         string            `json:"serviceName,omitempty"`
+	ServiceDescription  string            `json:"serviceDescription,omitempty"`
+	ApiVersion          string            `json:"apiVersion,omitempty"`
+	JsTextCase          string            `json:"jsTextCase,omitempty"`
+	Meta                map[string]string `json:"meta,omitempty"`
+}
+
+// PluginInfo lists the plugins loaded in the ServiceStack App
+type PluginInfo struct {
+	Loaded []string  `json:"loaded,omitempty"`
+	Auth   *AuthInfo `json:"auth,omitempty"`
+}
+
+// AuthInfo describes the App's AuthFeature configuration
+type AuthInfo struct {
+	HasAuthSecret     bool               `json:"hasAuthSecret,omitempty"`
+	HasAuthRepository bool               `json:"hasAuthRepository,omitempty"`
+	AuthProviders     []AuthProviderInfo `json:"authProviders,omitempty"`
+}
+
+// AuthProviderInfo describes a registered auth provider
+type AuthProviderInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// HasPlugin reports whether the plugin with the given id is loaded
+func (m *AppMetadata) HasPlugin(id string) bool {
+	for _, loaded := range m.Plugins.Loaded {
+		if loaded == id {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAppMetadata returns the server's app metadata from /metadata/app,
+// which is fetched once and cached for subsequent calls
+func (c *JsonServiceClient) GetAppMetadata() (*AppMetadata, error) {
+	if metadata := c.cachedAppMetadata(); metadata != nil {
+		return metadata, nil
+	}
+
+	// Concurrent calls wait for a single fetch without blocking other requests
+	c.state.metadataMu.Lock()
+	defer c.state.metadataMu.Unlock()
+	if metadata := c.cachedAppMetadata(); metadata != nil {
+		return metadata, nil
+	}
+
+	var metadata AppMetadata
+	if _, err := c.sendJSON(c.defaultContext(), http.MethodGet, "/metadata/app", nil, &metadata); err != nil {
+		return nil, err
+	}
+
+	c.state.mu.Lock()
+	c.state.appMetadata = &metadata
+	c.state.mu.Unlock()
+	return &metadata, nil
+}
+
+// cachedAppMetadata returns the app metadata fetched by GetAppMetadata
+func (c *JsonServiceClient) cachedAppMetadata() *AppMetadata {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	return c.state.appMetadata
+}
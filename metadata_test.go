@@ -0,0 +1,100 @@
+package servicestack
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetAppMetadata(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/metadata/app" {
+			t.Errorf("Expected path '/metadata/app', got '%s'", r.URL.Path)
+		}
+		io.WriteString(w, `{
+			"app": {"baseUrl":"https://localhost:5001","serviceStackVersion":"8.00","serviceName":"My App","apiVersion":"1.0"},
+			"plugins": {
+				"loaded": ["auth","autoquery","ui"],
+				"auth": {"hasAuthSecret":true,"authProviders":[{"name":"credentials","type":"credentials"}]}
+			}
+		}`)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	metadata, err := client.GetAppMetadata()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if metadata.App.ServiceName != "My App" {
+		t.Errorf("Expected service name 'My App', got '%s'", metadata.App.ServiceName)
+	}
+
+	if metadata.App.ServiceStackVersion != "8.00" {
+		t.Errorf("Expected version '8.00', got '%s'", metadata.App.ServiceStackVersion)
+	}
+
+	if !metadata.HasPlugin("autoquery") || metadata.HasPlugin("admin") {
+		t.Errorf("Expected only loaded plugins to be reported, got %v", metadata.Plugins.Loaded)
+	}
+
+	if metadata.Plugins.Auth == nil || metadata.Plugins.Auth.AuthProviders[0].Name != "credentials" {
+		t.Errorf("Expected credentials auth provider, got %v", metadata.Plugins.Auth)
+	}
+
+	if _, err := client.GetAppMetadata(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected metadata to be fetched once, got %d requests", requests)
+	}
+}
+
+func TestGetAppMetadataDoesNotBlockRequests(t *testing.T) {
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metadata/app" {
+			close(fetching)
+			<-release
+			io.WriteString(w, `{"app":{"serviceName":"Test"}}`)
+			return
+		}
+		io.WriteString(w, `{"result":"OK"}`)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetAppMetadata()
+		done <- err
+	}()
+	<-fetching
+
+	sent := make(chan error, 1)
+	go func() {
+		_, err := client.Get(&Hello{})
+		sent <- err
+	}()
+	select {
+	case err := <-sent:
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected requests not to wait for the metadata fetch")
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
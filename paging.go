@@ -0,0 +1,139 @@
+package servicestack
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Paging holds the pagination info returned in response headers
+type Paging struct {
+	// TotalCount is the X-Total-Count header, or -1 if it wasn't returned
+	TotalCount int
+	// Next, Prev, First and Last are the URLs of the Link header relations
+	Next  string
+	Prev  string
+	First string
+	Last  string
+}
+
+// SendWithPaging sends the request DTO like Send, returning its response
+// along with the pagination info from the X-Total-Count and Link headers
+func (c *JsonServiceClient) SendWithPaging(method string, request IReturn) (interface{}, *Paging, error) {
+	response := request.ResponseType()
+	resp, err := c.send(c.defaultContext(), method, request, response)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, parsePaging(resp.Header), nil
+}
+
+// parsePaging reads the pagination info from the response headers
+func parsePaging(header http.Header) *Paging {
+	paging := &Paging{TotalCount: -1}
+	if totalCount, err := strconv.Atoi(header.Get("X-Total-Count")); err == nil {
+		paging.TotalCount = totalCount
+	}
+
+	for rel, link := range parseLinkHeader(header.Get("Link")) {
+		switch rel {
+		case "next":
+			paging.Next = link
+		case "prev", "previous":
+			paging.Prev = link
+		case "first":
+			paging.First = link
+		case "last":
+			paging.Last = link
+		}
+	}
+	return paging
+}
+
+// parseLinkHeader parses an RFC 8288 Link header, e.g.
+// `<https://host/items?page=2>; rel="next"`, into URLs by relation
+func parseLinkHeader(value string) map[string]string {
+	links := map[string]string{}
+	for _, link := range strings.Split(value, ",") {
+		target, params, _ := strings.Cut(strings.TrimSpace(link), ";")
+		target = strings.TrimSpace(target)
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		target = target[1 : len(target)-1]
+
+		for _, param := range strings.Split(params, ";") {
+			name, rel, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if !strings.EqualFold(name, "rel") {
+				continue
+			}
+			for _, rel := range strings.Fields(strings.Trim(rel, `"`)) {
+				links[strings.ToLower(rel)] = target
+			}
+		}
+	}
+	return links
+}
+
+// Paginate sends the AutoQuery request DTO as GET requests for consecutive
+// pages of pageSize results, setting its Skip and Take fields, and invokes
+// onPage with the Results of each page until a page has fewer than pageSize
+// results. The request DTO itself isn't modified.
+func (c *JsonServiceClient) Paginate(request IReturn, pageSize int, onPage func(results interface{}) error) error {
+	if pageSize <= 0 {
+		return fmt.Errorf("invalid page size %d", pageSize)
+	}
+
+	v := reflect.ValueOf(request)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected a pointer to a request DTO struct, got %T", request)
+	}
+	page := reflect.New(v.Elem().Type())
+	page.Elem().Set(v.Elem())
+
+	for skip := 0; ; skip += pageSize {
+		if err := setIntField(page.Elem(), "Skip", skip); err != nil {
+			return err
+		}
+		if err := setIntField(page.Elem(), "Take", pageSize); err != nil {
+			return err
+		}
+
+		pageRequest := page.Interface().(IReturn)
+		response := pageRequest.ResponseType()
+		if _, err := c.send(c.defaultContext(), http.MethodGet, pageRequest, response); err != nil {
+			return err
+		}
+
+		results := reflect.Indirect(reflect.ValueOf(response)).FieldByName("Results")
+		if !results.IsValid() || results.Kind() != reflect.Slice {
+			return fmt.Errorf("response %T has no Results slice", response)
+		}
+		if err := onPage(results.Interface()); err != nil {
+			return err
+		}
+		if results.Len() < pageSize {
+			return nil
+		}
+	}
+}
+
+// setIntField sets the named int or *int field of the struct
+func setIntField(v reflect.Value, name string, value int) error {
+	field := v.FieldByName(name)
+	switch {
+	case !field.IsValid():
+		return fmt.Errorf("request %s has no %s field", v.Type(), name)
+	case field.Kind() == reflect.Int:
+		field.SetInt(int64(value))
+	case field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Int:
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().SetInt(int64(value))
+		field.Set(ptr)
+	default:
+		return fmt.Errorf("request %s field %s isn't an int", v.Type(), name)
+	}
+	return nil
+}
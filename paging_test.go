@@ -0,0 +1,110 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestJsonServiceClientSendWithPaging(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "42")
+		w.Header().Set("Link", `<https://api.example.com/items?page=3>; rel="next", <https://api.example.com/items?page=1>; rel="prev", <https://api.example.com/items?page=5>; rel="last"`)
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result, paging, err := client.SendWithPaging(http.MethodGet, &Hello{Name: "World"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response := result.(*HelloResponse); response.Result != "Hello" {
+		t.Errorf("Expected result 'Hello', got '%s'", response.Result)
+	}
+
+	if paging.TotalCount != 42 {
+		t.Errorf("Expected total count 42, got %d", paging.TotalCount)
+	}
+	if paging.Next != "https://api.example.com/items?page=3" {
+		t.Errorf("Expected next link, got '%s'", paging.Next)
+	}
+	if paging.Prev != "https://api.example.com/items?page=1" {
+		t.Errorf("Expected prev link, got '%s'", paging.Prev)
+	}
+	if paging.Last != "https://api.example.com/items?page=5" {
+		t.Errorf("Expected last link, got '%s'", paging.Last)
+	}
+	if paging.First != "" {
+		t.Errorf("Expected no first link, got '%s'", paging.First)
+	}
+}
+
+func TestParsePagingWithoutHeaders(t *testing.T) {
+	paging := parsePaging(http.Header{})
+
+	if paging.TotalCount != -1 {
+		t.Errorf("Expected total count -1, got %d", paging.TotalCount)
+	}
+	if paging.Next != "" || paging.Prev != "" {
+		t.Errorf("Expected no links, got %+v", paging)
+	}
+}
+
+type QueryCustomers struct {
+	NameContains string `json:"nameContains,omitempty"`
+	Skip         *int   `json:"skip,omitempty"`
+	Take         *int   `json:"take,omitempty"`
+}
+
+func (r *QueryCustomers) ResponseType() interface{} {
+	return &QueryCustomersResponse{}
+}
+
+type QueryCustomersResponse struct {
+	Offset  int      `json:"offset"`
+	Total   int      `json:"total"`
+	Results []string `json:"results"`
+}
+
+func TestJsonServiceClientPaginate(t *testing.T) {
+	customers := []string{"A", "B", "C", "D", "E"}
+
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("nameContains") != "x" {
+			t.Errorf("Expected nameContains 'x', got '%s'", r.URL.Query().Get("nameContains"))
+		}
+		skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+		take, _ := strconv.Atoi(r.URL.Query().Get("take"))
+		end := min(skip+take, len(customers))
+		json.NewEncoder(w).Encode(QueryCustomersResponse{Offset: skip, Total: len(customers), Results: customers[skip:end]})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	request := &QueryCustomers{NameContains: "x"}
+
+	var pages [][]string
+	err := client.Paginate(request, 2, func(results interface{}) error {
+		pages = append(pages, results.([]string))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(pages) != 3 {
+		t.Fatalf("Expected 3 pages, got %d", len(pages))
+	}
+	if len(pages[2]) != 1 || pages[2][0] != "E" {
+		t.Errorf("Expected last page [E], got %v", pages[2])
+	}
+	if request.Skip != nil || request.Take != nil {
+		t.Error("Expected the request DTO not to be modified")
+	}
+}
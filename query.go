@@ -0,0 +1,91 @@
+package servicestack
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// QueryParams are extra query string params sent with a request in addition
+// to the request DTO's fields, e.g. AutoQuery's implicit conventions
+type QueryParams url.Values
+
+// Add adds the value to the param
+func (q QueryParams) Add(name, value string) {
+	url.Values(q).Add(name, value)
+}
+
+// AddFilter adds an AutoQuery implicit convention param named after the field
+// and operator, e.g. AddFilter("Name", "Contains", "Jo") sends NameContains=Jo
+func (q QueryParams) AddFilter(name, op, value string) {
+	q.Add(name+op, value)
+}
+
+// SendWithQuery sends the request DTO like Send, adding the query params to
+// the request's query string
+func (c *JsonServiceClient) SendWithQuery(method string, request interface{}, responseType interface{}, query QueryParams) (interface{}, error) {
+	responseType = responseInstance(responseType)
+	ctx := context.WithValue(c.defaultContext(), requestQueryKey{}, query)
+	if _, err := c.send(ctx, method, request, responseType); err != nil {
+		return nil, err
+	}
+	return responseType, nil
+}
+
+// requestQueryKey is the context key of the extra query params of a request
+type requestQueryKey struct{}
+
+// appendRequestQuery appends the extra query params stored in ctx to the path
+func appendRequestQuery(ctx context.Context, path string) string {
+	query, _ := ctx.Value(requestQueryKey{}).(QueryParams)
+	if len(query) == 0 {
+		return path
+	}
+
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + url.Values(query).Encode()
+}
+
+// SetDefaultQueryParam sets a query param sent with all GET and DELETE
+// requests, e.g. a tenant id, and POST requests when IncludeQueryOnPost is set
+func (c *JsonServiceClient) SetDefaultQueryParam(name, value string) {
+	if c.DefaultQueryParams == nil {
+		c.DefaultQueryParams = map[string]string{}
+	}
+	c.DefaultQueryParams[name] = value
+}
+
+// appendDefaultQuery appends the DefaultQueryParams that aren't already on the
+// URL's query string
+func (c *JsonServiceClient) appendDefaultQuery(requestURL string) string {
+	if len(c.DefaultQueryParams) == 0 {
+		return requestURL
+	}
+
+	_, rawQuery, _ := strings.Cut(requestURL, "?")
+	existing, _ := url.ParseQuery(rawQuery)
+	names := make([]string, 0, len(c.DefaultQueryParams))
+	for name := range c.DefaultQueryParams {
+		if _, ok := existing[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return requestURL
+	}
+	sort.Strings(names)
+
+	values := url.Values{}
+	for _, name := range names {
+		values.Set(name, c.DefaultQueryParams[name])
+	}
+	separator := "?"
+	if strings.Contains(requestURL, "?") {
+		separator = "&"
+	}
+	return requestURL + separator + values.Encode()
+}
@@ -0,0 +1,153 @@
+package servicestack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestQueryParamsAddFilter(t *testing.T) {
+	query := QueryParams{}
+	query.AddFilter("Name", "Contains", "Jo")
+	query.AddFilter("Age", "GreaterThan", "18")
+	query.Add("OrderBy", "-Age")
+
+	expected := map[string]string{
+		"NameContains":   "Jo",
+		"AgeGreaterThan": "18",
+		"OrderBy":        "-Age",
+	}
+	for name, value := range expected {
+		if query[name][0] != value {
+			t.Errorf("Expected %s '%s', got '%s'", name, value, query[name][0])
+		}
+	}
+}
+
+func TestJsonServiceClientSendWithQuery(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("name") != "World" {
+			t.Errorf("Expected name 'World', got '%s'", query.Get("name"))
+		}
+		if query.Get("NameStartsWith") != "W" {
+			t.Errorf("Expected NameStartsWith 'W', got '%s'", query.Get("NameStartsWith"))
+		}
+		if query.Get("AgeLessThan") != "65" {
+			t.Errorf("Expected AgeLessThan '65', got '%s'", query.Get("AgeLessThan"))
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello"})
+	}))
+	defer server.Close()
+
+	query := QueryParams{}
+	query.AddFilter("Name", "StartsWith", "W")
+	query.AddFilter("Age", "LessThan", "65")
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.SendWithQuery(http.MethodGet, &Hello{Name: "World"}, &HelloResponse{}, query); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestJsonServiceClientSetDefaultQueryParam(t *testing.T) {
+	var queries []url.Values
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetDefaultQueryParam("tenantId", "acme")
+	client.SetDefaultQueryParam("name", "default")
+
+	if _, err := client.Get(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Delete(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Post(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client.IncludeQueryOnPost = true
+	if _, err := client.Post(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []struct {
+		tenantId string
+		name     string
+	}{
+		{"acme", "World"},
+		{"acme", "default"},
+		{"", ""},
+		{"acme", "World"},
+	}
+	for i, query := range queries {
+		if query.Get("tenantId") != expected[i].tenantId {
+			t.Errorf("Expected tenantId '%s' on request %d, got '%s'", expected[i].tenantId, i, query.Get("tenantId"))
+		}
+		if query.Get("name") != expected[i].name {
+			t.Errorf("Expected name '%s' on request %d, got '%s'", expected[i].name, i, query.Get("name"))
+		}
+		if len(query["name"]) > 1 {
+			t.Errorf("Expected a single name param on request %d, got %v", i, query["name"])
+		}
+	}
+}
+
+func TestJsonServiceClientDefaultQueryParamsOnPathRequests(t *testing.T) {
+	var tenants []string
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenants = append(tenants, r.URL.Query().Get("tenantId"))
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetDefaultQueryParam("tenantId", "acme")
+	client.PreferPostForLargeGets = true
+	client.MaxGetURLLength = 100
+
+	ctx := context.Background()
+	if err := client.GetPath(ctx, "/customers", &map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := client.DeletePath(ctx, "/customers/1", &map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetJSON("/customers", url.Values{"skip": {"10"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := client.Ping(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := client.Stream(&Hello{}, func(json.RawMessage) error { return nil }); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetAppMetadata(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// A large GET sent as a POST keeps the query params of a GET
+	if _, err := client.Get(&Hello{Name: strings.Repeat("a", 200)}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(tenants) != 7 {
+		t.Fatalf("Expected 7 requests, got %d", len(tenants))
+	}
+	for i, tenant := range tenants {
+		if tenant != "acme" {
+			t.Errorf("Expected tenantId 'acme' on request %d, got '%s'", i, tenant)
+		}
+	}
+}
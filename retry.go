@@ -0,0 +1,167 @@
+package servicestack
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how requests that fail with transient errors are
+// retried. Transport errors and 429, 502, 503 and 504 responses are retried,
+// waiting for the Retry-After delay when the server returns one.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the initial attempt
+	MaxRetries int
+	// Delay is the delay before the first retry, doubled after each retry
+	Delay time.Duration
+	// MaxDelay caps the delay between retries. 0 means backoff delays aren't
+	// capped and Retry-After delays are capped at one minute
+	MaxDelay time.Duration
+	// JitterFactor randomizes backoff delays by up to the fraction of the
+	// delay in either direction, e.g. 0.2 for ±20%, so clients retrying at the
+	// same time don't retry in lockstep. 0 disables jitter.
+	JitterFactor float64
+	// Rand returns the random numbers in [0, 1) used for jitter, e.g. the
+	// Float64 method of a seeded *rand.Rand in tests, defaults to math/rand
+	Rand func() float64
+}
+
+// NewRetryPolicy creates a RetryPolicy with the given max retries and initial delay
+func NewRetryPolicy(maxRetries int, delay time.Duration) *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries: maxRetries,
+		Delay:      delay,
+	}
+}
+
+// shouldRetry reports whether the attempt should be retried given its outcome
+func (p *RetryPolicy) shouldRetry(attempt int, resp *http.Response, err error) bool {
+	if p == nil || attempt >= p.MaxRetries {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return isRetryableStatus(resp.StatusCode)
+}
+
+// isRetryableStatus reports whether responses with the status code are
+// transient failures worth retrying
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// IsRetryable reports whether the error returned by a request is transient
+// and the request can be retried: timeouts, refused or reset connections, and
+// WebServiceExceptions for 429, 502, 503 and 504 responses
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, ErrClientClosed) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var webEx *WebServiceException
+	if errors.As(err, &webEx) {
+		return isRetryableStatus(webEx.StatusCode)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// defaultMaxRetryAfter caps Retry-After delays when MaxDelay isn't set so a
+// server can't stall a retry indefinitely
+const defaultMaxRetryAfter = time.Minute
+
+// retryDelay returns the delay before retrying the attempt, honoring the
+// response's Retry-After header when present
+func (p *RetryPolicy) retryDelay(attempt int, resp *http.Response, now time.Time) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			maxDelay := p.MaxDelay
+			if maxDelay <= 0 {
+				maxDelay = defaultMaxRetryAfter
+			}
+			if delay > maxDelay {
+				return maxDelay
+			}
+			return delay
+		}
+	}
+	return p.backoff(attempt)
+}
+
+// parseRetryAfter parses a Retry-After header in either its delay-seconds or
+// HTTP-date form into the delay from now
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// backoff returns the delay before the retry following the given attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.Delay << attempt
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay < 0) {
+		delay = p.MaxDelay
+	}
+	if p.JitterFactor > 0 {
+		delay = p.jitter(delay)
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			delay = p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// jitter randomizes the delay by up to JitterFactor of it in either direction
+func (p *RetryPolicy) jitter(delay time.Duration) time.Duration {
+	random := mathrand.Float64
+	if p.Rand != nil {
+		random = p.Rand
+	}
+	factor := 1 + p.JitterFactor*(2*random()-1)
+	if factor < 0 {
+		factor = 0
+	}
+	return time.Duration(float64(delay) * factor)
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,364 @@
+package servicestack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyRetriesTransientErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RetryPolicy = NewRetryPolicy(3, time.Millisecond)
+
+	result, err := client.Get(&Hello{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.(*HelloResponse).Result != "OK" {
+		t.Errorf("Expected result 'OK', got '%s'", result.(*HelloResponse).Result)
+	}
+
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryPolicyDoesNotRetryClientErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RetryPolicy = NewRetryPolicy(3, time.Millisecond)
+
+	if _, err := client.Get(&Hello{}); err == nil {
+		t.Fatal("Expected an error for 400 status code")
+	}
+
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{MaxRetries: 5, Delay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}
+	for attempt, delay := range expected {
+		if actual := policy.backoff(attempt); actual != delay {
+			t.Errorf("Expected backoff %v for attempt %d, got %v", delay, attempt, actual)
+		}
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		// Fail the first attempt of the first logical request
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RetryPolicy = NewRetryPolicy(1, time.Millisecond)
+	client.AddIdempotencyKey = true
+
+	if _, err := client.Post(&Hello{Name: "A"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Post(&Hello{Name: "B"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(keys) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(keys))
+	}
+
+	if keys[0] == "" {
+		t.Fatal("Expected Idempotency-Key header to be sent")
+	}
+
+	if keys[0] != keys[1] {
+		t.Errorf("Expected retried request to reuse key '%s', got '%s'", keys[0], keys[1])
+	}
+
+	if keys[2] == keys[0] {
+		t.Errorf("Expected a new key for a new request, got '%s' again", keys[2])
+	}
+}
+
+func TestRetryPolicyRetriesTooManyRequests(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if attempts == 2 {
+			w.Header().Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RetryPolicy = NewRetryPolicy(2, time.Hour)
+
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if delay, ok := parseRetryAfter("120", now); !ok || delay != 2*time.Minute {
+		t.Errorf("Expected 2m delay for seconds form, got %v", delay)
+	}
+
+	if delay, ok := parseRetryAfter("Mon, 01 Jan 2024 12:00:30 GMT", now); !ok || delay != 30*time.Second {
+		t.Errorf("Expected 30s delay for HTTP-date form, got %v", delay)
+	}
+
+	if delay, ok := parseRetryAfter("Mon, 01 Jan 2024 11:00:00 GMT", now); !ok || delay != 0 {
+		t.Errorf("Expected no delay for a past HTTP-date, got %v", delay)
+	}
+
+	if _, ok := parseRetryAfter("soon", now); ok {
+		t.Error("Expected invalid Retry-After not to be parsed")
+	}
+}
+
+func TestRetryPolicyRetryDelayCappedByMaxDelay(t *testing.T) {
+	policy := &RetryPolicy{MaxRetries: 1, Delay: time.Millisecond, MaxDelay: time.Second}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+
+	if delay := policy.retryDelay(0, resp, time.Now()); delay != time.Second {
+		t.Errorf("Expected delay capped at 1s, got %v", delay)
+	}
+}
+
+func TestRetryPolicyRetryDelayDefaultMaxRetryAfter(t *testing.T) {
+	policy := &RetryPolicy{MaxRetries: 1, Delay: time.Millisecond}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"86400"}}}
+
+	if delay := policy.retryDelay(0, resp, time.Now()); delay != defaultMaxRetryAfter {
+		t.Errorf("Expected delay capped at %v, got %v", defaultMaxRetryAfter, delay)
+	}
+}
+
+func TestRetryPolicySleepHonorsContext(t *testing.T) {
+	// Create a test server that asks the client to wait before retrying
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RetryPolicy = NewRetryPolicy(3, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.WithContext(ctx).Get(&Hello{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the retry wait to end with the context, took %v", elapsed)
+	}
+}
+
+func TestRetryPolicySleepHonorsClose(t *testing.T) {
+	// Create a test server that asks the client to wait before retrying
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RetryPolicy = NewRetryPolicy(3, time.Millisecond)
+	time.AfterFunc(50*time.Millisecond, client.Close)
+
+	start := time.Now()
+	_, err := client.Get(&Hello{})
+	if !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the retry wait to end on Close, took %v", elapsed)
+	}
+}
+
+// fakeClock is a clock that records sleeps and advances by them instantly
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return ctx.Err()
+}
+
+func TestRetryPolicyBackoffWithFakeClock(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 3 {
+			w.Header().Set("Retry-After", "30")
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	client := NewJsonServiceClient(server.URL)
+	client.clock = clock
+	client.RetryPolicy = &RetryPolicy{MaxRetries: 4, Delay: time.Second, MaxDelay: time.Minute}
+
+	if _, err := client.Get(&Hello{}); err == nil {
+		t.Fatal("Expected an error after exhausting retries")
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 30 * time.Second, 8 * time.Second}
+	if len(clock.sleeps) != len(expected) {
+		t.Fatalf("Expected %d sleeps, got %v", len(expected), clock.sleeps)
+	}
+	for i, delay := range expected {
+		if clock.sleeps[i] != delay {
+			t.Errorf("Expected sleep %d to be %v, got %v", i, delay, clock.sleeps[i])
+		}
+	}
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxRetries:   5,
+		Delay:        100 * time.Millisecond,
+		JitterFactor: 0.2,
+		Rand:         rand.New(rand.NewSource(1)).Float64,
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		base := 100 * time.Millisecond << attempt
+		min, max := time.Duration(float64(base)*0.8), time.Duration(float64(base)*1.2)
+		for i := 0; i < 100; i++ {
+			if delay := policy.backoff(attempt); delay < min || delay > max {
+				t.Errorf("Expected backoff for attempt %d within [%v, %v], got %v", attempt, min, max, delay)
+			}
+		}
+	}
+}
+
+func TestRetryPolicyBackoffJitterCappedByMaxDelay(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxRetries:   5,
+		Delay:        time.Second,
+		MaxDelay:     time.Second,
+		JitterFactor: 0.5,
+		Rand:         func() float64 { return 0.99 },
+	}
+
+	if delay := policy.backoff(3); delay != time.Second {
+		t.Errorf("Expected jittered delay capped at 1s, got %v", delay)
+	}
+}
+
+func TestIsRetryableTimeout(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetTimeout(10 * time.Millisecond)
+
+	_, err := client.Get(&Hello{})
+	if err == nil {
+		t.Fatal("Expected a timeout error")
+	}
+	if !IsRetryable(err) {
+		t.Errorf("Expected timeout error to be retryable, got %v", err)
+	}
+}
+
+func TestIsRetryableServiceUnavailable(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+
+	_, err := client.Get(&Hello{})
+	if !IsRetryable(err) {
+		t.Errorf("Expected 503 to be retryable, got %v", err)
+	}
+
+	_, err = client.Get(&Hello{Name: "invalid"})
+	if IsRetryable(err) {
+		t.Errorf("Expected 400 not to be retryable, got %v", err)
+	}
+}
+
+func TestIsRetryableConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	serverURL := server.URL
+	server.Close()
+
+	client := NewJsonServiceClient(serverURL)
+	if _, err := client.Get(&Hello{}); !IsRetryable(err) {
+		t.Errorf("Expected connection refused to be retryable, got %v", err)
+	}
+
+	if IsRetryable(nil) || IsRetryable(ErrClientClosed) {
+		t.Error("Expected nil and ErrClientClosed not to be retryable")
+	}
+}
@@ -0,0 +1,85 @@
+package servicestack
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// resolveRoute returns the request DTO's route with its {Field} placeholders
+// replaced by the values of the matching fields, along with the names of the
+// substituted fields
+func (c *JsonServiceClient) resolveRoute(request interface{}) (string, map[string]bool) {
+	return substituteRoute(c.getRequestPath(request), request)
+}
+
+// substituteRoute replaces the path's {Field} placeholders with the values of
+// the request DTO's matching fields, returning the names of the substituted
+// fields
+func substituteRoute(path string, request interface{}) (string, map[string]bool) {
+	if !strings.Contains(path, "{") {
+		return path, nil
+	}
+
+	v := reflect.ValueOf(request)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return path, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return path, nil
+	}
+
+	pathFields := map[string]bool{}
+	var route strings.Builder
+	for {
+		start := strings.Index(path, "{")
+		length := strings.Index(path[max(start, 0):], "}")
+		if start < 0 || length < 0 {
+			route.WriteString(path)
+			break
+		}
+		end := start + length
+
+		placeholder := path[start+1 : end]
+		route.WriteString(path[:start])
+		if field, value, ok := routeField(v, strings.TrimSuffix(placeholder, "*")); ok {
+			route.WriteString(url.PathEscape(queryValue(value)))
+			pathFields[field] = true
+		} else {
+			route.WriteString(path[start : end+1])
+		}
+		path = path[end+1:]
+	}
+	return route.String(), pathFields
+}
+
+// routeField finds the struct field matching the route placeholder by its Go
+// or JSON name, ignoring case and flattening embedded structs
+func routeField(v reflect.Value, name string) (string, reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+		if field.Anonymous && fieldValue.Kind() == reflect.Struct {
+			if found, value, ok := routeField(fieldValue, name); ok {
+				return found, value, true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		jsonName, _ := jsonFieldName(field)
+		if strings.EqualFold(field.Name, name) || strings.EqualFold(jsonName, name) {
+			for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+				fieldValue = fieldValue.Elem()
+			}
+			return field.Name, fieldValue, true
+		}
+	}
+	return "", reflect.Value{}, false
+}
@@ -0,0 +1,149 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type UpdateUser struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+func (r *UpdateUser) ResponseType() interface{} {
+	return &HelloResponse{}
+}
+
+func TestJsonServiceClientRouteWithPathAndBody(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT method, got %s", r.Method)
+		}
+		if r.URL.Path != "/users/42" {
+			t.Errorf("Expected path '/users/42', got '%s'", r.URL.Path)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"Jane"}` {
+			t.Errorf("Expected body without the id, got '%s'", body)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Updated"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RegisterRoute(&UpdateUser{}, "/users/{Id}")
+
+	result, err := client.Put(&UpdateUser{Id: 42, Name: "Jane"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.(*HelloResponse).Result != "Updated" {
+		t.Errorf("Expected result 'Updated', got '%s'", result.(*HelloResponse).Result)
+	}
+}
+
+func TestJsonServiceClientRouteWithPathAndQuery(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/7" {
+			t.Errorf("Expected path '/users/7', got '%s'", r.URL.Path)
+		}
+		if r.URL.RawQuery != "name=Jane" {
+			t.Errorf("Expected query 'name=Jane', got '%s'", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RegisterRoute(&UpdateUser{}, "/users/{id}")
+
+	if _, err := client.Get(&UpdateUser{Id: 7, Name: "Jane"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+type GetCustomer struct {
+	Id      int    `json:"id"`
+	Include string `json:"include,omitempty"`
+}
+
+func (r *GetCustomer) ResponseType() interface{} { return &HelloResponse{} }
+func (r *GetCustomer) Route() string             { return "/customers/{Id}" }
+func (r *GetCustomer) RouteVerb() string         { return http.MethodGet }
+
+type SearchCustomers struct {
+	Query string `json:"query"`
+}
+
+func (r *SearchCustomers) ResponseType() interface{} { return &HelloResponse{} }
+func (r *SearchCustomers) Route() string             { return "/customers/search" }
+
+func TestJsonServiceClientIRoute(t *testing.T) {
+	var method, path, query string
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, query = r.Method, r.URL.Path, r.URL.RawQuery
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+
+	if _, err := client.SendRequest(&GetCustomer{Id: 7, Include: "orders"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if method != http.MethodGet {
+		t.Errorf("Expected RouteVerb GET method, got %s", method)
+	}
+	if path != "/customers/7" {
+		t.Errorf("Expected path '/customers/7', got '%s'", path)
+	}
+	if query != "include=orders" {
+		t.Errorf("Expected query 'include=orders', got '%s'", query)
+	}
+
+	if _, err := client.Get(&SearchCustomers{Query: "Jo"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if path != "/customers/search" {
+		t.Errorf("Expected path '/customers/search', got '%s'", path)
+	}
+	if query != "query=Jo" {
+		t.Errorf("Expected query 'query=Jo', got '%s'", query)
+	}
+
+	client.RegisterRoute(&SearchCustomers{}, "/search")
+	if _, err := client.Get(&SearchCustomers{Query: "Jo"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if path != "/search" {
+		t.Errorf("Expected registered route '/search' to take precedence, got '%s'", path)
+	}
+}
+
+func TestJsonServiceClientSendAllIgnoresIRoute(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json/reply/GetCustomer[]" {
+			t.Errorf("Expected path '/json/reply/GetCustomer[]', got '%s'", r.URL.Path)
+		}
+		w.Write([]byte(`[{"result":"A"},{"result":"B"}]`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	results, err := client.SendAll([]IReturn{&GetCustomer{Id: 1}, &GetCustomer{Id: 2}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+}
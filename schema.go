@@ -0,0 +1,63 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// validateResponseSchema logs a warning for each field of the JSON object in
+// body that isn't a field of the response DTO, e.g. when the server's DTOs
+// are newer than the client's
+func (c *JsonServiceClient) validateResponseSchema(body []byte, response interface{}) {
+	t := reflect.TypeOf(response)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return
+	}
+
+	known := map[string]bool{}
+	addSchemaFields(known, t)
+
+	var unknown []string
+	for name := range fields {
+		if !known[strings.ToLower(name)] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		c.logf("servicestack: response field %q isn't in %s", name, t)
+	}
+}
+
+// addSchemaFields adds the lowercase JSON names of the struct's fields to
+// known, flattening embedded structs
+func addSchemaFields(known map[string]bool, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" && fieldType.Kind() == reflect.Struct {
+			addSchemaFields(known, fieldType)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name, skip := jsonFieldName(field); !skip {
+			known[strings.ToLower(name)] = true
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package servicestack
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testLogger struct {
+	messages []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestJsonServiceClientValidateResponseSchema(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Result":"Hello","responseStatus":null,"greetingCount":2}`))
+	}))
+	defer server.Close()
+
+	logger := &testLogger{}
+	client := NewJsonServiceClient(server.URL)
+	client.Logger = logger
+
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(logger.messages) != 0 {
+		t.Errorf("Expected no warnings when validation is disabled, got %v", logger.messages)
+	}
+
+	client.ValidateResponseSchema = true
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `servicestack: response field "greetingCount" isn't in servicestack.HelloResponse`
+	if len(logger.messages) != 1 || logger.messages[0] != expected {
+		t.Errorf("Expected warning '%s', got %v", expected, logger.messages)
+	}
+}
@@ -0,0 +1,107 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Serializer serializes request and response bodies, e.g. to use a faster
+// JSON library than encoding/json
+type Serializer interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONSerializer is the Serializer backed by encoding/json
+type JSONSerializer struct{}
+
+// Marshal marshals v with json.Marshal
+func (JSONSerializer) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal unmarshals data with json.Unmarshal
+func (JSONSerializer) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// unmarshal deserializes data with the client's Serializer, or encoding/json
+// if it isn't set
+func (c *JsonServiceClient) unmarshal(data []byte, v interface{}) error {
+	if c.Serializer != nil {
+		return c.Serializer.Unmarshal(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// serializerRequest returns the value the Serializer marshals for the request
+// DTO: the DTO itself, or a map of the fields that are sent when some aren't,
+// e.g. fields in the route's path, zero fields of PatchPartial requests and
+// fields tagged `servicestack:"ignore"`
+func serializerRequest(request interface{}) interface{} {
+	var omit map[string]bool
+	if partial, ok := request.(*partialRequest); ok {
+		request, omit = partial.request, partial.omit
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(request))
+	if v.Kind() != reflect.Struct || (len(omit) == 0 && !hasIgnoredFields(v.Type())) {
+		return request
+	}
+	fields := map[string]interface{}{}
+	addSentFields(v, omit, fields)
+	return fields
+}
+
+// hasIgnoredFields reports whether the struct type or its embedded structs
+// have fields tagged `servicestack:"ignore"`
+func hasIgnoredFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if hasTagOption(field, "ignore") {
+			return true
+		}
+		embedded := field.Type
+		if embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+		if field.Anonymous && embedded.Kind() == reflect.Struct && hasIgnoredFields(embedded) {
+			return true
+		}
+	}
+	return false
+}
+
+// addSentFields adds the struct's sent fields to fields by their JSON name,
+// flattening untagged embedded structs like marshalJSON
+func addSentFields(v reflect.Value, omit map[string]bool, fields map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldValue := v.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || hasTagOption(field, "ignore") {
+			continue
+		}
+
+		if field.Anonymous && tag == "" {
+			if embedded := reflect.Indirect(fieldValue); embedded.Kind() == reflect.Struct {
+				addSentFields(embedded, omit, fields)
+				continue
+			}
+		}
+
+		if !field.IsExported() || omit[field.Name] {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(opts, "omitempty") && isEmptyValue(fieldValue) {
+			continue
+		}
+		fields[name] = fieldValue.Interface()
+	}
+}
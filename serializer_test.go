@@ -0,0 +1,114 @@
+package servicestack
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// recordingSerializer is a JSONSerializer that records the types it serializes
+type recordingSerializer struct {
+	JSONSerializer
+	marshalled   []string
+	unmarshalled []string
+}
+
+func (s *recordingSerializer) Marshal(v interface{}) ([]byte, error) {
+	s.marshalled = append(s.marshalled, typeName(v))
+	return s.JSONSerializer.Marshal(v)
+}
+
+func (s *recordingSerializer) Unmarshal(data []byte, v interface{}) error {
+	s.unmarshalled = append(s.unmarshalled, typeName(v))
+	return s.JSONSerializer.Unmarshal(data, v)
+}
+
+func TestJsonServiceClientSerializer(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json/reply/Hello":
+			w.Write([]byte(`{"result":"Hello"}`))
+		case "/json/reply/Hello[]":
+			w.Write([]byte(`[{"result":"A"}]`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"responseStatus":{"errorCode":"Invalid","message":"Invalid request"}}`))
+		}
+	}))
+	defer server.Close()
+
+	serializer := &recordingSerializer{}
+	client := NewJsonServiceClient(server.URL)
+	client.Serializer = serializer
+
+	if _, err := client.Post(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.SendAll([]IReturn{&Hello{Name: "A"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, err := client.Post(&UpdateUser{Id: 1})
+	var webEx *WebServiceException
+	if !errors.As(err, &webEx) || webEx.ResponseStatus.ErrorCode != "Invalid" {
+		t.Fatalf("Expected 'Invalid' WebServiceException, got %v", err)
+	}
+
+	expectedMarshalled := []string{"Hello", "", "UpdateUser"}
+	if len(serializer.marshalled) != len(expectedMarshalled) {
+		t.Fatalf("Expected marshalled %v, got %v", expectedMarshalled, serializer.marshalled)
+	}
+	for i, name := range expectedMarshalled {
+		if serializer.marshalled[i] != name {
+			t.Errorf("Expected marshalled %d to be '%s', got '%s'", i, name, serializer.marshalled[i])
+		}
+	}
+
+	expectedUnmarshalled := []string{"HelloResponse", "", "ErrorResponse", "HelloResponse", "ErrorResponse"}
+	if len(serializer.unmarshalled) != len(expectedUnmarshalled) {
+		t.Fatalf("Expected unmarshalled %v, got %v", expectedUnmarshalled, serializer.unmarshalled)
+	}
+	for i, name := range expectedUnmarshalled {
+		if serializer.unmarshalled[i] != name {
+			t.Errorf("Expected unmarshalled %d to be '%s', got '%s'", i, name, serializer.unmarshalled[i])
+		}
+	}
+}
+
+func TestJsonServiceClientSerializerOmitsFields(t *testing.T) {
+	var bodies []string
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.Serializer = JSONSerializer{}
+	client.RegisterRoute(&UpdateUser{}, "/users/{Id}")
+
+	if _, err := client.PatchPartial(&PatchCustomer{Id: 1, Email: "jane@example.com"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Put(&UpdateUser{Id: 42, Name: "Jane"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Send(http.MethodPost, &SaveDraft{Title: "Draft", Selected: true, Scroll: 120}, &map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{
+		`{"email":"jane@example.com","id":1}`,
+		`{"name":"Jane"}`,
+		`{"body":"","title":"Draft"}`,
+	}
+	for i, body := range expected {
+		if bodies[i] != body {
+			t.Errorf("Expected body %s, got %s", body, bodies[i])
+		}
+	}
+}
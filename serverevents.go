@@ -0,0 +1,355 @@
+package servicestack
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ServerEventMessage is a message received from ServiceStack ServerEvents.
+// Messages are sent as "{Selector} {Json}" where the selector has the form
+// "[Channel@]{Op}.{Target}", e.g. "cmd.chat" or "home@tv.watch".
+type ServerEventMessage struct {
+	EventId  int64
+	Event    string
+	Data     string
+	Channel  string
+	Selector string
+	Json     string
+	Op       string
+	Target   string
+}
+
+// ServerEventConnect is the cmd.onConnect message sent when a subscription
+// is established
+type ServerEventConnect struct {
+	ServerEventMessage  `json:"-"`
+	Id                  string `json:"id"`
+	UserId              string `json:"userId"`
+	DisplayName         string `json:"displayName"`
+	IsAuthenticated     bool   `json:"isAuthenticated"`
+	UnRegisterUrl       string `json:"unRegisterUrl"`
+	UpdateSubscriberUrl string `json:"updateSubscriberUrl"`
+	HeartbeatUrl        string `json:"heartbeatUrl"`
+	HeartbeatIntervalMs int64  `json:"heartbeatIntervalMs"`
+	IdleTimeoutMs       int64  `json:"idleTimeoutMs"`
+}
+
+// ServerEventHeartbeat is the cmd.onHeartbeat message sent in response to a
+// heartbeat
+type ServerEventHeartbeat struct {
+	ServerEventMessage `json:"-"`
+}
+
+// ServerEventsClient subscribes to ServiceStack ServerEvents at /event-stream,
+// reconnecting with backoff when the connection is lost and sending
+// heartbeats to keep the subscription alive
+type ServerEventsClient struct {
+	BaseURL  string
+	Channels []string
+	// ServiceClient sends the heartbeat and unregister requests, its headers
+	// and cookies are also used for the event stream
+	ServiceClient *JsonServiceClient
+
+	// OnConnect is called when the subscription is established
+	OnConnect func(*ServerEventConnect)
+	// OnMessage is called for every message received
+	OnMessage func(*ServerEventMessage)
+	// OnHeartbeat is called when a heartbeat is acknowledged
+	OnHeartbeat func(*ServerEventHeartbeat)
+	// OnCommand is called for cmd.* messages other than onConnect and onHeartbeat
+	OnCommand func(*ServerEventMessage)
+	// OnException is called when the event stream or a heartbeat fails
+	OnException func(error)
+
+	// ReconnectDelay is the delay before the first reconnect attempt,
+	// doubled after each failed attempt up to MaxReconnectDelay
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+
+	mu             sync.Mutex
+	connectionInfo *ServerEventConnect
+	cancel         context.CancelFunc
+	done           chan struct{}
+}
+
+// NewServerEventsClient creates a ServerEventsClient subscribing to the channels
+func NewServerEventsClient(baseURL string, channels ...string) *ServerEventsClient {
+	return &ServerEventsClient{
+		BaseURL:           baseURL,
+		Channels:          channels,
+		ServiceClient:     NewJsonServiceClient(baseURL),
+		ReconnectDelay:    500 * time.Millisecond,
+		MaxReconnectDelay: 30 * time.Second,
+	}
+}
+
+// EventStreamURL returns the URL of the event stream subscribing to Channels
+func (c *ServerEventsClient) EventStreamURL() string {
+	streamURL := joinURL(c.BaseURL, "/event-stream")
+	if len(c.Channels) > 0 {
+		streamURL = appendQueryParam(streamURL, "channels", strings.Join(c.Channels, ","))
+	}
+	return streamURL
+}
+
+// ConnectionInfo returns the info of the current subscription, or nil if
+// it isn't connected
+func (c *ServerEventsClient) ConnectionInfo() *ServerEventConnect {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.connectionInfo
+}
+
+// Start connects to the event stream in the background
+func (c *ServerEventsClient) Start() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.done = make(chan struct{})
+	go c.run(ctx, c.done)
+}
+
+// Stop unregisters the subscription and closes the event stream
+func (c *ServerEventsClient) Stop() {
+	c.mu.Lock()
+	cancel, done, info := c.cancel, c.done, c.connectionInfo
+	c.cancel, c.done, c.connectionInfo = nil, nil, nil
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	if info != nil && info.UnRegisterUrl != "" {
+		c.postToUrl(info.UnRegisterUrl)
+	}
+	cancel()
+	<-done
+}
+
+// run reads the event stream until ctx is cancelled, reconnecting with
+// backoff whenever the connection is lost
+func (c *ServerEventsClient) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	delay := c.ReconnectDelay
+	for {
+		connected, err := c.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.onException(err)
+		}
+
+		if connected {
+			delay = c.ReconnectDelay
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if c.MaxReconnectDelay > 0 && delay > c.MaxReconnectDelay {
+			delay = c.MaxReconnectDelay
+		}
+	}
+}
+
+// connect reads the event stream until it's closed, reporting whether the
+// subscription was established
+func (c *ServerEventsClient) connect(ctx context.Context) (connected bool, err error) {
+	req, err := c.ServiceClient.newRequest(ctx, http.MethodGet, c.EventStreamURL(), nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The event stream is long-lived so it can't use the service client's timeout
+	httpClient := &http.Client{
+		Transport: c.ServiceClient.HTTPClient.Transport,
+		Jar:       c.ServiceClient.HTTPClient.Jar,
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to event stream: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return false, parseError(resp.StatusCode, resp.Status, body)
+	}
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	defer stopHeartbeat()
+
+	err = readServerEvents(resp.Body, func(msg *ServerEventMessage) {
+		if msg.Selector == "cmd.onConnect" {
+			connected = true
+			if info := c.handleConnect(msg); info != nil && info.HeartbeatIntervalMs > 0 {
+				go c.heartbeat(heartbeatCtx, info)
+			}
+			return
+		}
+		c.handleMessage(msg)
+	})
+	if err != nil {
+		return connected, fmt.Errorf("failed to read event stream: %w", err)
+	}
+	return connected, nil
+}
+
+// handleConnect stores the connection info of a cmd.onConnect message
+func (c *ServerEventsClient) handleConnect(msg *ServerEventMessage) *ServerEventConnect {
+	var info ServerEventConnect
+	if err := json.Unmarshal([]byte(msg.Json), &info); err != nil {
+		c.onException(fmt.Errorf("failed to parse onConnect message: %w", err))
+		return nil
+	}
+	info.ServerEventMessage = *msg
+
+	c.mu.Lock()
+	c.connectionInfo = &info
+	c.mu.Unlock()
+
+	if c.OnConnect != nil {
+		c.OnConnect(&info)
+	}
+	if c.OnMessage != nil {
+		c.OnMessage(msg)
+	}
+	return &info
+}
+
+// handleMessage dispatches a message to the registered callbacks
+func (c *ServerEventsClient) handleMessage(msg *ServerEventMessage) {
+	if msg.Selector == "cmd.onHeartbeat" {
+		if c.OnHeartbeat != nil {
+			c.OnHeartbeat(&ServerEventHeartbeat{ServerEventMessage: *msg})
+		}
+	} else if msg.Op == "cmd" && c.OnCommand != nil {
+		c.OnCommand(msg)
+	}
+
+	if c.OnMessage != nil {
+		c.OnMessage(msg)
+	}
+}
+
+// heartbeat sends heartbeats at the interval requested by the server until
+// ctx is cancelled
+func (c *ServerEventsClient) heartbeat(ctx context.Context, info *ServerEventConnect) {
+	ticker := time.NewTicker(time.Duration(info.HeartbeatIntervalMs) * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.postToUrl(info.HeartbeatUrl); err != nil && ctx.Err() == nil {
+				c.onException(fmt.Errorf("failed to send heartbeat: %w", err))
+			}
+		}
+	}
+}
+
+// postToUrl sends an empty POST request to the absolute or relative URL
+func (c *ServerEventsClient) postToUrl(rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if !target.IsAbs() {
+		rawURL = joinURL(c.BaseURL, rawURL)
+	}
+
+	req, err := c.ServiceClient.newRequest(c.ServiceClient.rootContext(), http.MethodPost, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.ServiceClient.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer drainBody(resp)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return parseError(resp.StatusCode, resp.Status, nil)
+	}
+	return nil
+}
+
+// onException reports the error to OnException
+func (c *ServerEventsClient) onException(err error) {
+	if c.OnException != nil {
+		c.OnException(err)
+	}
+}
+
+// readServerEvents parses the text/event-stream protocol, invoking onMessage
+// for each event until the stream ends
+func readServerEvents(r io.Reader, onMessage func(*ServerEventMessage)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var msg ServerEventMessage
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				event := msg
+				event.Data = strings.Join(data, "\n")
+				parseServerEventData(&event)
+				onMessage(&event)
+			}
+			msg, data = ServerEventMessage{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			msg.EventId, _ = strconv.ParseInt(value, 10, 64)
+		case "event":
+			msg.Event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseServerEventData splits the message data into its selector and JSON
+func parseServerEventData(msg *ServerEventMessage) {
+	selector, body, _ := strings.Cut(msg.Data, " ")
+	msg.Selector, msg.Json = selector, body
+
+	target := selector
+	if channel, rest, ok := strings.Cut(selector, "@"); ok {
+		msg.Channel, target = channel, rest
+	}
+	msg.Op, msg.Target, _ = strings.Cut(target, ".")
+}
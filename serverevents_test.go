@@ -0,0 +1,179 @@
+package servicestack
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeEventServer is a minimal ServiceStack ServerEvents server
+type fakeEventServer struct {
+	*httptest.Server
+	connections  int32
+	heartbeats   int32
+	unregistered int32
+	// closeAfterMessages closes the stream after the messages are sent
+	closeAfterMessages bool
+}
+
+func newFakeEventServer(closeAfterMessages bool) *fakeEventServer {
+	s := &fakeEventServer{closeAfterMessages: closeAfterMessages}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/event-stream":
+			s.stream(w, r)
+		case "/event-heartbeat":
+			atomic.AddInt32(&s.heartbeats, 1)
+		case "/event-unregister":
+			atomic.AddInt32(&s.unregistered, 1)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return s
+}
+
+func (s *fakeEventServer) stream(w http.ResponseWriter, r *http.Request) {
+	id := atomic.AddInt32(&s.connections, 1)
+	w.Header().Set("Content-Type", "text/event-stream")
+
+	fmt.Fprintf(w, "id: 1\ndata: cmd.onConnect {\"id\":\"sub%d\",\"userId\":\"-1\",\"displayName\":\"user1\","+
+		"\"heartbeatUrl\":\"%s/event-heartbeat?id=sub%d\",\"unRegisterUrl\":\"%s/event-unregister?id=sub%d\","+
+		"\"heartbeatIntervalMs\":20,\"channels\":\"%s\"}\n\n", id, s.URL, id, s.URL, id, r.URL.Query().Get("channels"))
+	fmt.Fprint(w, ": comment lines are ignored\n\n")
+	fmt.Fprint(w, "id: 2\ndata: home@cmd.chat {\"message\":\"Hello\"}\n\n")
+	fmt.Fprint(w, "id: 3\nevent: update\ndata: tv.watch {\"url\":\n")
+	fmt.Fprint(w, "data: \"https://youtu.be\"}\n\n")
+	fmt.Fprint(w, "id: 4\ndata: cmd.onHeartbeat {}\n\n")
+	w.(http.Flusher).Flush()
+
+	if s.closeAfterMessages {
+		return
+	}
+	<-r.Context().Done()
+}
+
+func TestServerEventsClient(t *testing.T) {
+	server := newFakeEventServer(false)
+	defer server.Close()
+
+	var mu sync.Mutex
+	var connect *ServerEventConnect
+	var messages, commands []*ServerEventMessage
+	heartbeats := 0
+	connected := make(chan struct{})
+
+	client := NewServerEventsClient(server.URL, "home", "work")
+	client.OnConnect = func(info *ServerEventConnect) {
+		mu.Lock()
+		defer mu.Unlock()
+		connect = info
+		close(connected)
+	}
+	client.OnMessage = func(msg *ServerEventMessage) {
+		mu.Lock()
+		defer mu.Unlock()
+		messages = append(messages, msg)
+	}
+	client.OnCommand = func(msg *ServerEventMessage) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, msg)
+	}
+	client.OnHeartbeat = func(*ServerEventHeartbeat) {
+		mu.Lock()
+		defer mu.Unlock()
+		heartbeats++
+	}
+
+	client.Start()
+	select {
+	case <-connected:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected client to connect")
+	}
+
+	// Wait for a few heartbeats to be sent
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&server.heartbeats) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	client.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if connect.Id != "sub1" || connect.DisplayName != "user1" {
+		t.Errorf("Expected connection 'sub1' for 'user1', got '%s' for '%s'", connect.Id, connect.DisplayName)
+	}
+
+	if !strings.HasSuffix(connect.HeartbeatUrl, "/event-heartbeat?id=sub1") {
+		t.Errorf("Expected heartbeat URL for sub1, got '%s'", connect.HeartbeatUrl)
+	}
+
+	if len(messages) != 4 {
+		t.Fatalf("Expected 4 messages, got %d", len(messages))
+	}
+
+	chat := messages[1]
+	if chat.EventId != 2 || chat.Channel != "home" || chat.Op != "cmd" || chat.Target != "chat" || chat.Json != `{"message":"Hello"}` {
+		t.Errorf("Expected chat message on 'home', got %+v", chat)
+	}
+
+	watch := messages[2]
+	if watch.Event != "update" || watch.Selector != "tv.watch" || watch.Json != "{\"url\":\n\"https://youtu.be\"}" {
+		t.Errorf("Expected multi-line tv.watch message, got %+v", watch)
+	}
+
+	if len(commands) != 1 || commands[0].Target != "chat" {
+		t.Errorf("Expected 1 chat command, got %v", commands)
+	}
+
+	if heartbeats != 1 {
+		t.Errorf("Expected 1 heartbeat message, got %d", heartbeats)
+	}
+
+	if atomic.LoadInt32(&server.heartbeats) < 2 {
+		t.Errorf("Expected heartbeats to be sent, got %d", server.heartbeats)
+	}
+
+	if atomic.LoadInt32(&server.unregistered) != 1 {
+		t.Errorf("Expected subscription to be unregistered on Stop, got %d", server.unregistered)
+	}
+}
+
+func TestServerEventsClientReconnects(t *testing.T) {
+	server := newFakeEventServer(true)
+	defer server.Close()
+
+	var connects int32
+	client := NewServerEventsClient(server.URL)
+	client.ReconnectDelay = 10 * time.Millisecond
+	client.OnConnect = func(*ServerEventConnect) {
+		atomic.AddInt32(&connects, 1)
+	}
+
+	client.Start()
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadInt32(&connects) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	client.Stop()
+
+	if atomic.LoadInt32(&connects) < 3 {
+		t.Errorf("Expected client to reconnect, got %d connections", connects)
+	}
+}
+
+func TestServerEventsClientEventStreamURL(t *testing.T) {
+	client := NewServerEventsClient("https://host/", "home", "work")
+
+	if url := client.EventStreamURL(); url != "https://host/event-stream?channels=home%2Cwork" {
+		t.Errorf("Expected 'https://host/event-stream?channels=home%%2Cwork', got '%s'", url)
+	}
+}
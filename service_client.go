@@ -0,0 +1,1773 @@
+package servicestack
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cookie names ServiceStack uses to store JWT tokens
+const (
+	TokenCookie        = "ss-tok"
+	RefreshTokenCookie = "ss-reftok"
+)
+
+// ErrClientClosed is returned for requests sent after the client was closed
+var ErrClientClosed = errors.New("servicestack: client closed")
+
+// ErrNotAuthenticated matches WebServiceExceptions for 401 Unauthorized
+// responses, including redirects to a login page
+var ErrNotAuthenticated = errors.New("servicestack: not authenticated")
+
+// ErrConcurrencyConflict matches WebServiceExceptions for 412 Precondition
+// Failed responses, e.g. when the If-Match ETag sent with SendWithIfMatch is
+// no longer current
+var ErrConcurrencyConflict = errors.New("servicestack: concurrency conflict")
+
+// ErrResponseTooLarge is returned when a response body exceeds MaxResponseBytes
+var ErrResponseTooLarge = errors.New("servicestack: response body too large")
+
+// ErrUnexpectedContentType is returned when a response isn't JSON and
+// OnNonJSONResponse isn't set
+var ErrUnexpectedContentType = errors.New("servicestack: unexpected response content type")
+
+// JsonServiceClient is a typed ServiceStack client that routes request DTOs
+// to ServiceStack's predefined /json/reply/{Type} routes
+type JsonServiceClient struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Headers    map[string]string
+
+	// BearerToken is the token sent in the Authorization header, set by
+	// SetBearerToken or Authenticate
+	BearerToken string
+	// RefreshToken is the refresh token returned by Authenticate, used to
+	// request a new BearerToken when it expires
+	RefreshToken string
+	// RefreshMargin refreshes the BearerToken with the RefreshToken before
+	// sending a request when its JWT expires within the margin, instead of
+	// waiting for the request to fail with a 401
+	RefreshMargin time.Duration
+	// SessionId is sent in the X-ss-id header to authenticate with a session
+	// without cookies, set by SetSessionId or by Authenticate and Login when
+	// the HTTPClient has no cookie jar
+	SessionId string
+
+	// AuthSecret is sent with every request to access the service in admin mode
+	AuthSecret string
+	// AuthSecretInQuery sends the AuthSecret as a query param instead of a header
+	AuthSecretInQuery bool
+	// UseApiEndpoint routes requests to /api/{Type} instead of /json/reply/{Type}
+	UseApiEndpoint bool
+	// RetryPolicy configures automatic retries of transient failures, nil disables retries
+	RetryPolicy *RetryPolicy
+	// AddIdempotencyKey sends a unique Idempotency-Key header with each
+	// non-GET request, reused across its automatic retries
+	AddIdempotencyKey bool
+	// Cache stores GET responses with an ETag, revalidating them with If-None-Match
+	Cache Cache
+	// OnBasicAuthChallenge is called when a request is rejected with a Basic
+	// auth challenge to supply credentials for the realm. When ok is true the
+	// credentials are used for the rest of the client's requests and the
+	// request is retried once.
+	OnBasicAuthChallenge func(realm string) (user, pass string, ok bool)
+	// OnAuthenticationRequired is called when a request fails with a 401 to
+	// authenticate again, e.g. by calling Login with stored credentials. When
+	// it returns nil the request is retried once. Unlike refreshing an expired
+	// BearerToken with the RefreshToken, it re-runs the full login.
+	OnAuthenticationRequired func() error
+	// ErrorParser converts error responses into errors, overriding the
+	// default parsing of ServiceStack's ResponseStatus
+	ErrorParser func(statusCode int, status string, body []byte, header http.Header) error
+	// MaxResponseBytes limits the size of response bodies, 0 means unlimited
+	MaxResponseBytes int64
+	// SignRequest is called with each request and its body just before it's
+	// sent, e.g. to add an HMAC signature header
+	SignRequest func(req *http.Request, body []byte)
+	// IncludeQueryOnPost also sends the DTO's fields on the query string of
+	// POST requests, e.g. for AutoQuery services reading paging params
+	IncludeQueryOnPost bool
+	// PreferPostForLargeGets sends GET requests whose URL is longer than
+	// MaxGetURLLength as POST requests with the DTO in the body, overriding
+	// the method with the X-Http-Method-Override header so they're still
+	// handled as GET requests
+	PreferPostForLargeGets bool
+	// MaxGetURLLength is the longest GET URL sent when PreferPostForLargeGets
+	// is set, defaults to 2048
+	MaxGetURLLength int
+	// TreatResponseStatusAsError returns a WebServiceException for successful
+	// responses whose ResponseStatus has an ErrorCode
+	TreatResponseStatusAsError bool
+	// Serializer serializes request and response bodies, nil uses
+	// encoding/json extended with UseCamelCaseNames, enum names, the
+	// servicestack:"ignore" tag and StrictResponseDecoding. Requests with
+	// fields that aren't sent, e.g. ignored, route and PatchPartial fields,
+	// are passed to it as a map of their sent fields.
+	Serializer Serializer
+	// StrictResponseDecoding rejects responses containing fields that aren't
+	// in the response DTO, e.g. to validate service contracts in tests
+	StrictResponseDecoding bool
+	// ValidateResponseSchema logs a warning for response fields that aren't in
+	// the response DTO to catch version skew between the server and client DTOs
+	ValidateResponseSchema bool
+	// Logger receives the client's warnings, defaults to the standard logger
+	Logger Logger
+	// Debug logs each request and response with their bodies to the Logger
+	Debug bool
+	// MaxLoggedBodyBytes truncates bodies logged when Debug is set, defaults
+	// to 1024
+	MaxLoggedBodyBytes int
+	// MaxConcurrency limits the number of requests in flight at once, e.g.
+	// when sending many async requests, 0 means unlimited
+	MaxConcurrency int
+	// UrlFilter rewrites the URL of each request after it's built, e.g. to
+	// add a cache-busting param or route requests through a mirror
+	UrlFilter func(url string) string
+	// FollowRedirects follows redirect responses, defaults to true. The
+	// Authorization and authsecret headers aren't sent to other hosts. When
+	// disabled, redirects are returned as a WebServiceException with their Location.
+	FollowRedirects bool
+	// PingPath is the health check path requested by Ping, defaults to "/"
+	PingPath string
+	// UseCamelCaseNames serializes request fields without an explicit json
+	// name in camelCase instead of their Go field name
+	UseCamelCaseNames bool
+	// OmitDefaultContentType and OmitDefaultAccept don't send the default
+	// application/json Content-Type and Accept headers, leaving them to the
+	// client's Headers, e.g. for gateways requiring exact header values
+	OmitDefaultContentType bool
+	OmitDefaultAccept      bool
+	// OnNonJSONResponse decodes successful responses whose Content-Type isn't
+	// JSON, e.g. XML, into the response DTO. When nil they return an
+	// ErrUnexpectedContentType error.
+	OnNonJSONResponse func(contentType string, body []byte, response interface{}) error
+	// ForceHTTP1 disables HTTP/2 on the HTTPClient's transport, e.g. for
+	// middleboxes that misbehave with h2
+	ForceHTTP1 bool
+	// WriteBaseURL is the base URL of POST, PUT, PATCH and DELETE requests,
+	// e.g. to send writes to a different host than reads, defaults to BaseURL
+	WriteBaseURL string
+	// TrustedHosts are the hosts besides those of BaseURL and WriteBaseURL
+	// the Authorization, authsecret and X-ss-id credentials are sent to, e.g.
+	// hosts requests are rewritten to by UrlFilter
+	TrustedHosts []string
+	// SendAuthToAllHosts sends the client's credentials to any host
+	SendAuthToAllHosts bool
+	// DefaultQueryParams are sent on the query string of all GET and DELETE
+	// requests, and POST requests when IncludeQueryOnPost is set, unless the
+	// request already has a param with the same name
+	DefaultQueryParams map[string]string
+	// CaptureRequestOnError stores the JSON request body of requests failing
+	// with a 5xx status in the WebServiceException's RequestBody, replacing
+	// the values of fields tagged `servicestack:"secret"` with [REDACTED]
+	CaptureRequestOnError bool
+	// OnUploadProgress is called as the body of a PostStream upload is sent
+	// with the bytes sent so far and the total, or -1 when the length isn't
+	// known
+	OnUploadProgress func(bytesSent, total int64)
+
+	// ctx is the root context of all requests, cancelled by Close
+	ctx    context.Context
+	cancel context.CancelFunc
+	// callCtx is the context of requests sent without a per-call context
+	callCtx context.Context
+	// scoped is set on the copies returned by WithContext
+	scoped bool
+	// clock is used for retry delays and timings, nil uses the real clock
+	clock clock
+
+	// state is shared with the copies returned by WithContext
+	state *clientState
+}
+
+// clientState is the mutable state of a client shared by its copies
+type clientState struct {
+	mu          sync.Mutex
+	appMetadata *AppMetadata
+	routes      map[string]string
+
+	// metadataMu serializes fetching the app metadata
+	metadataMu sync.Mutex
+
+	// authenticating is set while OnAuthenticationRequired is running
+	authenticating atomic.Bool
+	// refreshing is set while an expiring BearerToken is being refreshed
+	refreshing atomic.Bool
+
+	// http1Transport is the transport ForceHTTP1 last disabled HTTP/2 on
+	http1Mu        sync.Mutex
+	http1Transport *http.Transport
+
+	// headersMu guards the Headers, BearerToken, RefreshToken, SessionId and
+	// AuthSecret, which are updated while requests are in flight, e.g. by Basic
+	// auth challenges and token refreshes
+	headersMu sync.RWMutex
+
+	// slots limits concurrent requests to MaxConcurrency
+	slotsMu sync.Mutex
+	slots   chan struct{}
+}
+
+// NewJsonServiceClient creates a new JsonServiceClient with the given base URL
+func NewJsonServiceClient(baseURL string) *JsonServiceClient {
+	jar := newClientJar()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &JsonServiceClient{
+		BaseURL: baseURL,
+		HTTPClient: &http.Client{
+			Timeout:       30 * time.Second,
+			Jar:           jar,
+			CheckRedirect: checkRedirect,
+		},
+		Headers:         make(map[string]string),
+		FollowRedirects: true,
+		ctx:             ctx,
+		cancel:          cancel,
+		state:           &clientState{},
+	}
+}
+
+// followRedirectsKey is the context key of the client's FollowRedirects setting
+type followRedirectsKey struct{}
+
+// redirectClientKey is the context key of the client whose TrustedHosts
+// redirects are checked against
+type redirectClientKey struct{}
+
+// checkRedirect stops redirects when the request's client disabled
+// FollowRedirects and removes credentials from redirects to hosts that aren't
+// trusted by the client
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if follow, ok := req.Context().Value(followRedirectsKey{}).(bool); ok && !follow {
+		return http.ErrUseLastResponse
+	}
+	if isLoginURL(req.URL) {
+		return http.ErrUseLastResponse
+	}
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	trusted := req.URL.Host == via[0].URL.Host
+	if c, ok := req.Context().Value(redirectClientKey{}).(*JsonServiceClient); ok {
+		trusted = c.isTrustedHost(req.URL)
+	}
+	if !trusted {
+		req.Header.Del("Authorization")
+		req.Header.Del("authsecret")
+		req.Header.Del("X-ss-id")
+	}
+	return nil
+}
+
+// WithContext returns a shallow copy of the client whose requests are bound
+// to ctx unless they're sent with a per-call context, e.g. to propagate the
+// deadline and tracing of an incoming request to all calls made while
+// handling it. The copy shares the HTTPClient, cookies and cache of the
+// client but has its own Headers. Closing the copy doesn't close the client.
+func (c *JsonServiceClient) WithContext(ctx context.Context) *JsonServiceClient {
+	clone := *c
+	clone.callCtx = ctx
+	clone.scoped = true
+	c.state.headersMu.RLock()
+	clone.Headers = make(map[string]string, len(c.Headers))
+	for key, value := range c.Headers {
+		clone.Headers[key] = value
+	}
+	c.state.headersMu.RUnlock()
+	if c.DefaultQueryParams != nil {
+		clone.DefaultQueryParams = make(map[string]string, len(c.DefaultQueryParams))
+		for name, value := range c.DefaultQueryParams {
+			clone.DefaultQueryParams[name] = value
+		}
+	}
+	return &clone
+}
+
+// defaultContext returns the context for requests sent without a per-call context
+func (c *JsonServiceClient) defaultContext() context.Context {
+	if c.callCtx == nil {
+		return context.Background()
+	}
+	return c.callCtx
+}
+
+// Close cancels all in-flight requests and closes idle connections. Requests
+// sent after the client is closed return ErrClientClosed. Close is a no-op on
+// the copies returned by WithContext, which are closed with their client.
+func (c *JsonServiceClient) Close() {
+	if c.scoped {
+		return
+	}
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.HTTPClient.CloseIdleConnections()
+}
+
+// isClosed reports whether the client has been closed
+func (c *JsonServiceClient) isClosed() bool {
+	return c.ctx != nil && c.ctx.Err() != nil
+}
+
+// rootContext returns the root context for the client's requests
+func (c *JsonServiceClient) rootContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// requestContext returns a context for a request that's cancelled when
+// either ctx or the client's root context is cancelled
+func (c *JsonServiceClient) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(c.rootContext(), cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// SetTimeout sets the timeout for all requests
+func (c *JsonServiceClient) SetTimeout(timeout time.Duration) {
+	c.HTTPClient.Timeout = timeout
+}
+
+// SetBearerToken sets the bearer token sent in the Authorization header
+func (c *JsonServiceClient) SetBearerToken(token string) {
+	c.state.headersMu.Lock()
+	defer c.state.headersMu.Unlock()
+	c.BearerToken = token
+	c.Headers["Authorization"] = "Bearer " + token
+}
+
+// SetCredentials sets the username and password used for Basic authentication
+func (c *JsonServiceClient) SetCredentials(username, password string) {
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	c.state.headersMu.Lock()
+	defer c.state.headersMu.Unlock()
+	c.Headers["Authorization"] = "Basic " + credentials
+}
+
+// ClearAuth removes the client's credentials, e.g. when switching users: the
+// Authorization header, BearerToken, RefreshToken, SessionId, AuthSecret and
+// the session cookies in the cookie jar the client created. A cookie jar
+// assigned to HTTPClient.Jar isn't cleared, callers must reset it themselves.
+func (c *JsonServiceClient) ClearAuth() {
+	c.state.headersMu.Lock()
+	delete(c.Headers, "Authorization")
+	c.BearerToken = ""
+	c.RefreshToken = ""
+	c.SessionId = ""
+	c.AuthSecret = ""
+	c.state.headersMu.Unlock()
+
+	if jar, ok := c.HTTPClient.Jar.(*clientJar); ok {
+		jar.reset()
+	}
+}
+
+// clientJar is the cookie jar created by NewJsonServiceClient, which can be
+// reset in place by ClearAuth without replacing HTTPClient.Jar
+type clientJar struct {
+	mu  sync.RWMutex
+	jar *cookiejar.Jar
+}
+
+func newClientJar() *clientJar {
+	jar, _ := cookiejar.New(nil)
+	return &clientJar{jar: jar}
+}
+
+func (j *clientJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	j.jar.SetCookies(u, cookies)
+}
+
+func (j *clientJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	return j.jar.Cookies(u)
+}
+
+// reset discards all cookies
+func (j *clientJar) reset() {
+	jar, _ := cookiejar.New(nil)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar = jar
+}
+
+// ClearHeaders removes all custom headers, including the Authorization header
+func (c *JsonServiceClient) ClearHeaders() {
+	c.state.headersMu.Lock()
+	defer c.state.headersMu.Unlock()
+	c.Headers = make(map[string]string)
+}
+
+// SetHeader sets a custom header for all requests
+func (c *JsonServiceClient) SetHeader(key, value string) {
+	c.state.headersMu.Lock()
+	defer c.state.headersMu.Unlock()
+	c.Headers[key] = value
+}
+
+// SetHeaders merges the headers into the headers sent with all requests,
+// overwriting existing headers with the same name
+func (c *JsonServiceClient) SetHeaders(headers map[string]string) {
+	c.state.headersMu.Lock()
+	defer c.state.headersMu.Unlock()
+	for key, value := range headers {
+		c.Headers[key] = value
+	}
+}
+
+// SetSessionId sends the session id in the X-ss-id header of all requests,
+// authenticating with the session without cookies
+func (c *JsonServiceClient) SetSessionId(id string) {
+	c.state.headersMu.Lock()
+	defer c.state.headersMu.Unlock()
+	c.SessionId = id
+}
+
+// SetWriteBaseURL sends POST, PUT, PATCH and DELETE requests to the base URL
+// instead of BaseURL, e.g. for deployments splitting reads and writes. An
+// empty URL sends all requests to BaseURL.
+func (c *JsonServiceClient) SetWriteBaseURL(url string) {
+	c.WriteBaseURL = url
+}
+
+// SetReferer sets the Referer header sent with all requests
+func (c *JsonServiceClient) SetReferer(url string) {
+	c.SetHeader("Referer", url)
+}
+
+// SetOrigin sets the Origin header sent with all requests, e.g. for services
+// restricting CORS requests to allowed origins
+func (c *JsonServiceClient) SetOrigin(url string) {
+	c.SetHeader("Origin", url)
+}
+
+// SetAuthSecret sets the AuthSecret sent with every request for admin access
+func (c *JsonServiceClient) SetAuthSecret(secret string) {
+	c.state.headersMu.Lock()
+	defer c.state.headersMu.Unlock()
+	c.AuthSecret = secret
+}
+
+// SetTokenCookie stores a cookie for the BaseURL in the client's cookie jar,
+// e.g. a previously persisted ss-tok or ss-reftok token
+func (c *JsonServiceClient) SetTokenCookie(name, value string) {
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil || c.HTTPClient.Jar == nil {
+		return
+	}
+	c.HTTPClient.Jar.SetCookies(baseURL, []*http.Cookie{{Name: name, Value: value, Path: "/"}})
+}
+
+// GetTokenCookie returns the value of the named cookie stored for the BaseURL,
+// or an empty string if it doesn't exist
+func (c *JsonServiceClient) GetTokenCookie(name string) string {
+	baseURL, err := url.Parse(c.BaseURL)
+	if err != nil || c.HTTPClient.Jar == nil {
+		return ""
+	}
+	for _, cookie := range c.HTTPClient.Jar.Cookies(baseURL) {
+		if cookie.Name == name {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// Get sends the request DTO as a GET request
+func (c *JsonServiceClient) Get(request IReturn) (interface{}, error) {
+	return c.Send(http.MethodGet, request, request.ResponseType())
+}
+
+// Post sends the request DTO as a POST request
+func (c *JsonServiceClient) Post(request IReturn) (interface{}, error) {
+	return c.Send(http.MethodPost, request, request.ResponseType())
+}
+
+// Put sends the request DTO as a PUT request
+func (c *JsonServiceClient) Put(request IReturn) (interface{}, error) {
+	return c.Send(http.MethodPut, request, request.ResponseType())
+}
+
+// Delete sends the request DTO as a DELETE request
+func (c *JsonServiceClient) Delete(request IReturn) (interface{}, error) {
+	return c.Send(http.MethodDelete, request, request.ResponseType())
+}
+
+// Patch sends the request DTO as a PATCH request
+func (c *JsonServiceClient) Patch(request IReturn) (interface{}, error) {
+	return c.Send(http.MethodPatch, request, request.ResponseType())
+}
+
+// GetInto sends the request DTO as a GET request, unmarshalling the
+// response into the provided response pointer
+func (c *JsonServiceClient) GetInto(request IReturn, response interface{}) error {
+	_, err := c.send(c.defaultContext(), http.MethodGet, request, response)
+	return err
+}
+
+// PostInto sends the request DTO as a POST request, unmarshalling the
+// response into the provided response pointer
+func (c *JsonServiceClient) PostInto(request IReturn, response interface{}) error {
+	_, err := c.send(c.defaultContext(), http.MethodPost, request, response)
+	return err
+}
+
+// PutInto sends the request DTO as a PUT request, unmarshalling the
+// response into the provided response pointer
+func (c *JsonServiceClient) PutInto(request IReturn, response interface{}) error {
+	_, err := c.send(c.defaultContext(), http.MethodPut, request, response)
+	return err
+}
+
+// DeleteInto sends the request DTO as a DELETE request, unmarshalling the
+// response into the provided response pointer
+func (c *JsonServiceClient) DeleteInto(request IReturn, response interface{}) error {
+	_, err := c.send(c.defaultContext(), http.MethodDelete, request, response)
+	return err
+}
+
+// PatchInto sends the request DTO as a PATCH request, unmarshalling the
+// response into the provided response pointer
+func (c *JsonServiceClient) PatchInto(request IReturn, response interface{}) error {
+	_, err := c.send(c.defaultContext(), http.MethodPatch, request, response)
+	return err
+}
+
+// GetPath sends a GET request to the path relative to BaseURL, unmarshalling
+// the response into the provided response pointer
+func (c *JsonServiceClient) GetPath(ctx context.Context, path string, response interface{}) error {
+	_, err := c.sendJSON(ctx, http.MethodGet, path, nil, response)
+	return err
+}
+
+// PostPath sends the request as the JSON body of a POST request to the path
+// relative to BaseURL, unmarshalling the response into the provided pointer
+func (c *JsonServiceClient) PostPath(ctx context.Context, path string, request, response interface{}) error {
+	_, err := c.sendJSON(ctx, http.MethodPost, path, request, response)
+	return err
+}
+
+// PutPath sends the request as the JSON body of a PUT request to the path
+// relative to BaseURL, unmarshalling the response into the provided pointer
+func (c *JsonServiceClient) PutPath(ctx context.Context, path string, request, response interface{}) error {
+	_, err := c.sendJSON(ctx, http.MethodPut, path, request, response)
+	return err
+}
+
+// DeletePath sends a DELETE request to the path relative to BaseURL,
+// unmarshalling the response into the provided response pointer
+func (c *JsonServiceClient) DeletePath(ctx context.Context, path string, response interface{}) error {
+	_, err := c.sendJSON(ctx, http.MethodDelete, path, nil, response)
+	return err
+}
+
+// PatchPath sends the request as the JSON body of a PATCH request to the path
+// relative to BaseURL, unmarshalling the response into the provided pointer
+func (c *JsonServiceClient) PatchPath(ctx context.Context, path string, request, response interface{}) error {
+	_, err := c.sendJSON(ctx, http.MethodPatch, path, request, response)
+	return err
+}
+
+// GetJSON sends a GET request to the path relative to BaseURL with the query
+// params, returning the response as a generic JSON object for services
+// without a response DTO
+func (c *JsonServiceClient) GetJSON(path string, query url.Values) (map[string]interface{}, error) {
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var response map[string]interface{}
+	if _, err := c.sendJSON(c.defaultContext(), http.MethodGet, path, nil, &response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// SendMap sends the fields as a request of the named type without a request
+// DTO, e.g. for scripting, and unmarshals the response into responseType.
+// Like Send, GET and DELETE requests send the fields on the query string and
+// other methods send them as the JSON body.
+func (c *JsonServiceClient) SendMap(method, typeName string, fields map[string]interface{}, responseType interface{}) (interface{}, error) {
+	responseType = responseInstance(responseType)
+	path := c.typeRoute(typeName)
+
+	var request interface{} = fields
+	if !hasRequestBody(method) {
+		if queryString := mapQueryString(fields); queryString != "" {
+			path += "?" + queryString
+		}
+		request = nil
+	}
+
+	if _, err := c.sendJSON(c.defaultContext(), method, path, request, responseType); err != nil {
+		return nil, err
+	}
+	return responseType, nil
+}
+
+// mapQueryString serializes the non-nil fields into a query string, joining
+// slices with commas like DTO fields
+func mapQueryString(fields map[string]interface{}) string {
+	values := url.Values{}
+	for name, value := range fields {
+		if value == nil {
+			continue
+		}
+		v := reflect.ValueOf(value)
+		if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+			items := make([]string, v.Len())
+			for i := range items {
+				items[i] = queryValue(v.Index(i))
+			}
+			values.Set(name, strings.Join(items, ","))
+			continue
+		}
+		values.Set(name, queryValue(v))
+	}
+	return values.Encode()
+}
+
+// GetScalar sends the request DTO as a GET request and unmarshals the
+// response into out, which can point to any type including services that
+// return a bare JSON string or number, e.g. *string or *int
+func (c *JsonServiceClient) GetScalar(request IReturn, out interface{}) error {
+	_, err := c.send(c.defaultContext(), http.MethodGet, request, out)
+	return err
+}
+
+// Ping sends a GET request to PingPath, returning nil if the server responds
+// with a 2xx status, e.g. for readiness probes
+func (c *JsonServiceClient) Ping() error {
+	path := c.PingPath
+	if path == "" {
+		path = "/"
+	}
+	_, err := c.sendJSON(c.defaultContext(), http.MethodGet, path, nil, nil)
+	return err
+}
+
+// BatchResult is the outcome of a single request in a batch, holding either
+// its response or its error
+type BatchResult struct {
+	Response interface{}
+	Error    *WebServiceException
+}
+
+// IsSuccess reports whether the request succeeded
+func (r BatchResult) IsSuccess() bool {
+	return r.Error == nil
+}
+
+// PatchPartial sends the request DTO as a PATCH request containing only its
+// non-zero fields, for partial updates with JSON Merge Patch semantics
+func (c *JsonServiceClient) PatchPartial(request IReturn) (interface{}, error) {
+	partial := &partialRequest{request: request, omit: zeroFields(request)}
+	return c.Send(http.MethodPatch, partial, request.ResponseType())
+}
+
+// zeroFields returns the names of the request DTO's zero-valued fields,
+// flattening embedded structs
+func zeroFields(request interface{}) map[string]bool {
+	v := reflect.Indirect(reflect.ValueOf(request))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := map[string]bool{}
+	var add func(v reflect.Value)
+	add = func(v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				add(v.Field(i))
+			} else if v.Field(i).IsZero() {
+				fields[field.Name] = true
+			}
+		}
+	}
+	add(v)
+	return fields
+}
+
+// Warmup opens a connection to the server with a HEAD request to BaseURL so
+// the first request doesn't pay the TCP and TLS connection setup cost. Any
+// response is accepted, only failing to reach the server returns an error.
+func (c *JsonServiceClient) Warmup(ctx context.Context) error {
+	if c.isClosed() {
+		return ErrClientClosed
+	}
+
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
+	req, err := c.newRequest(ctx, http.MethodHead, c.BaseURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	drainBody(resp)
+	return nil
+}
+
+// SendAll sends all requests in a single batched request to /json/reply/{Type}[],
+// or /api/{Type}[] when UseApiEndpoint is set, even when the type has a
+// registered or IRoute route.
+// Requests whose response carries an error ResponseStatus have their Error
+// set, so the successful responses of a partially failed batch are still returned.
+func (c *JsonServiceClient) SendAll(requests []IReturn) ([]BatchResult, error) {
+	if len(requests) == 0 {
+		return []BatchResult{}, nil
+	}
+
+	var results []json.RawMessage
+	// Batches always use the predefined route, registered and IRoute routes
+	// don't support the [] suffix
+	path := c.typePath(typeName(requests[0])) + "[]"
+	resp, err := c.sendJSON(c.defaultContext(), http.MethodPost, path, requests, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) != len(requests) {
+		return nil, fmt.Errorf("expected %d responses, got %d", len(requests), len(results))
+	}
+
+	batch := make([]BatchResult, len(results))
+	for i, result := range results {
+		var errorResponse ErrorResponse
+		if err := c.unmarshal(result, &errorResponse); err == nil && !errorResponse.ResponseStatus.IsSuccess() {
+			batch[i].Error = &WebServiceException{
+				StatusCode:     resp.StatusCode,
+				ResponseStatus: errorResponse.ResponseStatus,
+				ResponseBody:   string(result),
+			}
+			continue
+		}
+
+		response := requests[i].ResponseType()
+		if err := c.unmarshalResponse(result, response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		batch[i].Response = response
+	}
+
+	return batch, nil
+}
+
+// SendAllTyped sends all requests in a single batched request, returning the
+// responses as a typed slice. It returns the first error of a partially
+// failed batch, use SendAll to access the successful responses.
+func SendAllTyped[TReq IReturn, TResp any](c *JsonServiceClient, requests []TReq) ([]*TResp, error) {
+	batch := make([]IReturn, len(requests))
+	for i, request := range requests {
+		batch[i] = request
+	}
+
+	results, err := c.SendAll(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*TResp, len(results))
+	for i, result := range results {
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		response, ok := result.Response.(*TResp)
+		if !ok {
+			return nil, fmt.Errorf("expected response of type %T, got %T", response, result.Response)
+		}
+		responses[i] = response
+	}
+	return responses, nil
+}
+
+// PublishAll sends all requests in a single batched request to the
+// /json/oneway/{Type}[] route for asynchronous processing
+func (c *JsonServiceClient) PublishAll(requests []interface{}) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	path := "/json/oneway/" + typeName(requests[0]) + "[]"
+	_, err := c.sendJSON(c.defaultContext(), http.MethodPost, path, requests, nil)
+	return err
+}
+
+// SendRequest sends the request DTO using the HTTP method declared by its
+// IGet, IPost, IPut, IDelete or IPatch marker, defaulting to POST
+func (c *JsonServiceClient) SendRequest(request IReturn) (interface{}, error) {
+	return c.Send(httpMethodOf(request, http.MethodPost), request, request.ResponseType())
+}
+
+// Send sends the request DTO using the given HTTP method and unmarshals the
+// response into responseType. Passing a sample value instead of a pointer,
+// e.g. HelloResponse{}, returns a pointer to a new instance of its type.
+//
+// GET and DELETE requests send the DTO's fields on the query string without
+// a body unless the DTO implements ISendAsBody, all other methods send the
+// DTO as the JSON body, and POST requests also send them on the query string
+// when IncludeQueryOnPost is set.
+// Requests implementing IGet are always sent as GET requests.
+func (c *JsonServiceClient) Send(method string, request interface{}, responseType interface{}) (interface{}, error) {
+	responseType = responseInstance(responseType)
+	if _, err := c.send(c.defaultContext(), method, request, responseType); err != nil {
+		return nil, err
+	}
+	return responseType, nil
+}
+
+// responseInstance returns a pointer to a new instance of the sample's type
+// when it's passed by value, e.g. HelloResponse{}, so the response has
+// somewhere to be unmarshalled into
+func responseInstance(sample interface{}) interface{} {
+	if sample == nil {
+		return nil
+	}
+	if t := reflect.TypeOf(sample); t.Kind() != reflect.Ptr {
+		return reflect.New(t).Interface()
+	}
+	return sample
+}
+
+// SendAs sends the request DTO using the given HTTP method, unmarshalling the
+// response into responseType instead of the type declared by the DTO's
+// ResponseType(). Use it for polymorphic endpoints whose response shape
+// depends on the request, or to read a response into a narrower type.
+func (c *JsonServiceClient) SendAs(method string, request interface{}, responseType interface{}) (interface{}, error) {
+	return c.Send(method, request, responseType)
+}
+
+// SendWithAccept sends the request DTO like Send, requesting the response
+// format in the Accept header instead of the default application/json
+func (c *JsonServiceClient) SendWithAccept(accept, method string, request interface{}, responseType interface{}) (interface{}, error) {
+	responseType = responseInstance(responseType)
+	ctx := withRequestHeader(c.defaultContext(), "Accept", accept)
+	if _, err := c.send(ctx, method, request, responseType); err != nil {
+		return nil, err
+	}
+	return responseType, nil
+}
+
+// SendWithIfMatch sends the request DTO like Send with the If-Match header
+// set to the etag, e.g. from a prior GET, so the server only applies an update
+// to the current version. A 412 Precondition Failed response returns an error
+// matching ErrConcurrencyConflict.
+func (c *JsonServiceClient) SendWithIfMatch(etag, method string, request interface{}, responseType interface{}) (interface{}, error) {
+	responseType = responseInstance(responseType)
+	ctx := withRequestHeader(c.defaultContext(), "If-Match", etag)
+	if _, err := c.send(ctx, method, request, responseType); err != nil {
+		return nil, err
+	}
+	return responseType, nil
+}
+
+// SendWithCookies sends the request DTO like Send, adding the cookies to this
+// request only without storing them in the client's cookie jar
+func (c *JsonServiceClient) SendWithCookies(method string, request interface{}, responseType interface{}, cookies []*http.Cookie) (interface{}, error) {
+	responseType = responseInstance(responseType)
+	ctx := context.WithValue(c.defaultContext(), requestCookiesKey{}, cookies)
+	if _, err := c.send(ctx, method, request, responseType); err != nil {
+		return nil, err
+	}
+	return responseType, nil
+}
+
+// SendTimed sends the request DTO like Send, also returning the round-trip
+// time from sending the request to reading the response body
+func (c *JsonServiceClient) SendTimed(method string, request interface{}, responseType interface{}) (interface{}, time.Duration, error) {
+	responseType = responseInstance(responseType)
+	var elapsed time.Duration
+	ctx := context.WithValue(c.defaultContext(), elapsedKey{}, &elapsed)
+	if _, err := c.send(ctx, method, request, responseType); err != nil {
+		return nil, elapsed, err
+	}
+	return responseType, elapsed, nil
+}
+
+// elapsedKey is the context key of the duration a request's round-trip time
+// is stored in
+type elapsedKey struct{}
+
+// send sends the request DTO to its route, returning the HTTP response
+func (c *JsonServiceClient) send(ctx context.Context, method string, request interface{}, responseType interface{}) (*http.Response, error) {
+	ctx, method, path, body := c.prepareRequest(ctx, method, request)
+	return c.sendJSON(ctx, method, path, body, responseType)
+}
+
+// prepareRequest returns the method, path and body the request DTO is sent
+// with, moving its fields to the route and query string as needed
+func (c *JsonServiceClient) prepareRequest(ctx context.Context, method string, request interface{}) (context.Context, string, string, interface{}) {
+	omit := map[string]bool{}
+	if partial, ok := request.(*partialRequest); ok {
+		request = partial.request
+		for name := range partial.omit {
+			omit[name] = true
+		}
+	}
+
+	if getRequest, ok := request.(IGet); ok && strings.EqualFold(getRequest.HttpMethod(), http.MethodGet) {
+		method = http.MethodGet
+	}
+	asBody := sendsAsBody(request)
+
+	// Fields substituted into the route aren't also sent in the body or query
+	path, pathFields := c.resolveRoute(request)
+	for name := range pathFields {
+		omit[name] = true
+	}
+	if len(omit) > 0 {
+		request = &partialRequest{request: request, omit: omit}
+	}
+
+	if method == http.MethodGet && c.isLargeGet(path, request) {
+		ctx = withRequestHeader(ctx, "X-Http-Method-Override", http.MethodGet)
+		method = http.MethodPost
+	} else if !hasRequestBody(method) && !asBody {
+		if queryString := toQueryString(request); queryString != "" {
+			path += "?" + queryString
+		}
+		request = nil
+	} else if method == http.MethodPost && c.IncludeQueryOnPost {
+		if queryString := toQueryString(request); queryString != "" {
+			path += "?" + queryString
+		}
+	}
+	path = appendRequestQuery(ctx, path)
+
+	return ctx, method, path, request
+}
+
+// sendsAsBody reports whether the request DTO is always sent as the body
+func sendsAsBody(request interface{}) bool {
+	bodyRequest, ok := request.(ISendAsBody)
+	return ok && bodyRequest.SendAsBody()
+}
+
+// isLargeGet reports whether the GET request's URL exceeds MaxGetURLLength
+// when PreferPostForLargeGets is set
+func (c *JsonServiceClient) isLargeGet(path string, request interface{}) bool {
+	if !c.PreferPostForLargeGets {
+		return false
+	}
+	maxLength := c.MaxGetURLLength
+	if maxLength <= 0 {
+		maxLength = 2048
+	}
+	requestURL := joinURL(c.BaseURL, path) + "?" + toQueryString(request)
+	return len(requestURL) > maxLength
+}
+
+// hasRequestBody reports whether requests with the method send the DTO as the
+// request body rather than on the query string
+func hasRequestBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// sendJSON sends the request to the path relative to BaseURL, marshalling a
+// non-nil request as the JSON body. The HTTP response is returned with its
+// body consumed whenever the server responded, including on errors.
+func (c *JsonServiceClient) sendJSON(ctx context.Context, method, path string, request, response interface{}) (*http.Response, error) {
+	if err := c.refreshExpiringToken(); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendJSONOnce(ctx, method, path, request, response)
+	if c.OnAuthenticationRequired == nil || !errors.Is(err, ErrNotAuthenticated) {
+		return resp, err
+	}
+
+	// Requests sent while authenticating aren't reauthenticated again
+	if !c.state.authenticating.CompareAndSwap(false, true) {
+		return resp, err
+	}
+	authErr := c.OnAuthenticationRequired()
+	c.state.authenticating.Store(false)
+	if authErr != nil {
+		return resp, fmt.Errorf("failed to reauthenticate: %w", authErr)
+	}
+	return c.sendJSONOnce(ctx, method, path, request, response)
+}
+
+// sendJSONOnce sends the request like sendJSON without reauthenticating
+func (c *JsonServiceClient) sendJSONOnce(ctx context.Context, method, path string, request, response interface{}) (*http.Response, error) {
+	if c.isClosed() {
+		return nil, ErrClientClosed
+	}
+
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+	ctx = context.WithValue(ctx, followRedirectsKey{}, c.FollowRedirects)
+	ctx = context.WithValue(ctx, redirectClientKey{}, c)
+
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	requestURL := c.requestURL(overriddenMethod(ctx, method), path)
+
+	// Prepare request body
+	var jsonData []byte
+	if request != nil {
+		var err error
+		jsonData, err = c.marshalRequest(request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+	if c.Debug {
+		loggedURL, loggedBody := c.loggedRequest(requestURL, request, jsonData)
+		c.logf("servicestack: %s %s %s", method, loggedURL, loggedBody)
+	}
+
+	// Reuse the same key across retries so the server can dedupe them
+	var idempotencyKey string
+	if c.AddIdempotencyKey && method != http.MethodGet && method != http.MethodHead {
+		idempotencyKey = newUUID()
+	}
+
+	// Look up a cached response to revalidate
+	var cached *CacheEntry
+	cacheKey := responseCacheKey(ctx, requestURL)
+	if c.Cache != nil && method == http.MethodGet {
+		cached, _ = c.Cache.Get(cacheKey)
+	}
+
+	// Execute request, retrying transient failures
+	var resp *http.Response
+	var sent time.Time
+	challenged := false
+	for attempt := 0; ; {
+		req, err := c.newRequest(ctx, method, requestURL, jsonData)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept-Encoding", "gzip")
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+		if cached != nil {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if c.SignRequest != nil {
+			c.SignRequest(req, jsonData)
+		}
+
+		sent = c.getClock().Now()
+		resp, err = c.do(req)
+
+		// Retry once with credentials supplied for a Basic auth challenge
+		if err == nil && !challenged && c.answerBasicAuthChallenge(resp) {
+			challenged = true
+			drainBody(resp)
+			continue
+		}
+
+		if err != nil && c.isClosed() {
+			return nil, ErrClientClosed
+		}
+		if err != nil && ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to execute request: %w", err)
+		}
+
+		if !c.RetryPolicy.shouldRetry(attempt, resp, err) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to execute request: %w", err)
+			}
+			break
+		}
+
+		drainBody(resp)
+		if err := c.getClock().Sleep(ctx, c.RetryPolicy.retryDelay(attempt, resp, c.getClock().Now())); err != nil {
+			if c.isClosed() {
+				return nil, ErrClientClosed
+			}
+			return nil, fmt.Errorf("failed to execute request: %w", err)
+		}
+		attempt++
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	respBody, err := c.readBody(resp)
+	if err != nil {
+		return resp, err
+	}
+	if c.Debug {
+		c.logf("servicestack: %s %s", resp.Status, c.loggedResponse(respBody))
+	}
+	if elapsed, ok := ctx.Value(elapsedKey{}).(*time.Duration); ok {
+		*elapsed = c.getClock().Now().Sub(sent)
+	}
+
+	// Use the cached response if it hasn't been modified
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		respBody = cached.Body
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := c.parseError(resp, respBody)
+		if c.CaptureRequestOnError && resp.StatusCode >= 500 {
+			c.captureRequest(err, request)
+		}
+		return resp, err
+	} else if c.Cache != nil && method == http.MethodGet {
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			c.Cache.Set(cacheKey, &CacheEntry{ETag: etag, Body: respBody})
+		}
+	}
+
+	// Unmarshal response
+	if response != nil && len(respBody) > 0 {
+		if err := c.decodeResponse(resp.Header.Get("Content-Type"), respBody, response); err != nil {
+			return resp, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+		if c.ValidateResponseSchema {
+			c.validateResponseSchema(respBody, response)
+		}
+	}
+
+	if c.TreatResponseStatusAsError {
+		if status := responseStatusOf(response); !status.IsSuccess() {
+			return resp, &WebServiceException{
+				StatusCode:        resp.StatusCode,
+				StatusDescription: strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode))),
+				ResponseStatus:    status,
+				ResponseBody:      string(respBody),
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// captureRequest stores the request body, with secret fields redacted, on
+// the WebServiceException for bug reports
+func (c *JsonServiceClient) captureRequest(err error, request interface{}) {
+	var webEx *WebServiceException
+	if request == nil || !errors.As(err, &webEx) {
+		return
+	}
+	if body, marshalErr := marshalRedacted(request, c.UseCamelCaseNames); marshalErr == nil {
+		webEx.RequestBody = string(body)
+	}
+}
+
+// responseCacheKey returns the key a GET response is cached under: its URL,
+// prefixed with the Accept header of requests overriding it, e.g. GetCSV, so
+// other formats aren't returned for JSON requests
+func responseCacheKey(ctx context.Context, requestURL string) string {
+	if accept := requestHeaders(ctx)["Accept"]; accept != "" {
+		return accept + " " + requestURL
+	}
+	return requestURL
+}
+
+// responseStatusOf returns the ResponseStatus field of a response DTO, or nil
+// if it doesn't have one
+func responseStatusOf(response interface{}) *ResponseStatus {
+	v := reflect.ValueOf(response)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	field := v.FieldByName("ResponseStatus")
+	if !field.IsValid() {
+		return nil
+	}
+	switch status := field.Interface().(type) {
+	case *ResponseStatus:
+		return status
+	case ResponseStatus:
+		return &status
+	}
+	return nil
+}
+
+// requestURL returns the URL of the path relative to the base URL of the
+// method, including the DefaultQueryParams and AuthSecret query params and
+// applying the UrlFilter
+func (c *JsonServiceClient) requestURL(method, path string) string {
+	requestURL := joinURL(c.baseURL(method), path)
+	if !hasRequestBody(method) || (method == http.MethodPost && c.IncludeQueryOnPost) {
+		requestURL = c.appendDefaultQuery(requestURL)
+	}
+	c.state.headersMu.RLock()
+	authSecret := c.AuthSecret
+	c.state.headersMu.RUnlock()
+	secretInQuery := authSecret != "" && c.AuthSecretInQuery
+	if secretInQuery {
+		requestURL = appendQueryParam(requestURL, "authsecret", authSecret)
+	}
+	if c.UrlFilter != nil {
+		requestURL = c.UrlFilter(requestURL)
+		if secretInQuery {
+			requestURL = c.removeUntrustedAuthSecret(requestURL)
+		}
+	}
+	return requestURL
+}
+
+// removeUntrustedAuthSecret removes the authsecret query param from URLs the
+// UrlFilter rewrote to an untrusted host
+func (c *JsonServiceClient) removeUntrustedAuthSecret(requestURL string) string {
+	u, err := url.Parse(requestURL)
+	if err != nil || c.isTrustedHost(u) {
+		return requestURL
+	}
+	query := u.Query()
+	query.Del("authsecret")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// overriddenMethod returns the X-Http-Method-Override of the request, e.g.
+// for large GETs sent as POST requests, or its method if it isn't overridden
+func overriddenMethod(ctx context.Context, method string) string {
+	if override := requestHeaders(ctx)["X-Http-Method-Override"]; override != "" {
+		return override
+	}
+	return method
+}
+
+// baseURL returns WriteBaseURL for POST, PUT, PATCH and DELETE requests when
+// it's set, and BaseURL otherwise
+func (c *JsonServiceClient) baseURL(method string) string {
+	if c.WriteBaseURL == "" {
+		return c.BaseURL
+	}
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return c.WriteBaseURL
+	}
+	return c.BaseURL
+}
+
+// readBody reads the response body, decompressing gzip responses and
+// enforcing MaxResponseBytes
+func (c *JsonServiceClient) readBody(resp *http.Response) ([]byte, error) {
+	reader, err := decompressBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if c.MaxResponseBytes <= 0 {
+		body, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return body, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, c.MaxResponseBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > c.MaxResponseBytes {
+		return nil, fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, c.MaxResponseBytes)
+	}
+	return body, nil
+}
+
+// decompressBody returns a reader of the decompressed response body. An
+// empty body declared as gzip, e.g. sent by some proxies on a 304, is read
+// as an empty body.
+func decompressBody(resp *http.Response) (io.Reader, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp.Body, nil
+	}
+
+	body := bufio.NewReader(resp.Body)
+	if _, err := body.Peek(1); err == io.EOF {
+		return body, nil
+	}
+	return gzip.NewReader(body)
+}
+
+// decodeResponse unmarshals the response body according to its Content-Type.
+// JSON responses, and text/plain responses containing valid JSON, are
+// unmarshalled into the response DTO and other content types are passed to
+// OnNonJSONResponse.
+func (c *JsonServiceClient) decodeResponse(contentType string, body []byte, response interface{}) error {
+	if raw, ok := response.(*rawBody); ok {
+		*raw = body
+		return nil
+	}
+	if c.Serializer != nil {
+		return c.unmarshalResponse(body, response)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "" || isJSONMediaType(mediaType) || (mediaType == "text/plain" && json.Valid(body)) {
+		return c.unmarshalResponse(body, response)
+	}
+	if c.OnNonJSONResponse != nil {
+		return c.OnNonJSONResponse(contentType, body, response)
+	}
+
+	snippet := string(body)
+	if len(snippet) > 100 {
+		snippet = snippet[:100] + "..."
+	}
+	return fmt.Errorf("%w %s: %s", ErrUnexpectedContentType, mediaType, snippet)
+}
+
+// isJSONMediaType reports whether the media type is JSON, e.g.
+// application/json or application/problem+json
+func isJSONMediaType(mediaType string) bool {
+	switch mediaType {
+	case "application/json", "text/json", "application/jsonl", "application/x-ndjson":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+json")
+}
+
+// unmarshalResponse deserializes the response body, rejecting unknown fields
+// when StrictResponseDecoding is set
+func (c *JsonServiceClient) unmarshalResponse(body []byte, response interface{}) error {
+	if c.Serializer != nil || !c.StrictResponseDecoding {
+		return c.unmarshal(body, response)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(response)
+}
+
+// marshalRequest serializes the request DTO into the JSON request body
+func (c *JsonServiceClient) marshalRequest(request interface{}) ([]byte, error) {
+	if c.Serializer != nil {
+		return c.Serializer.Marshal(serializerRequest(request))
+	}
+	if partial, ok := request.(*partialRequest); ok {
+		return marshalJSONOmitting(partial.request, c.UseCamelCaseNames, partial.omit)
+	}
+	return marshalJSON(request, c.UseCamelCaseNames)
+}
+
+// newRequest creates an HTTP request with the client's headers, sending a
+// non-nil body as JSON
+func (c *JsonServiceClient) newRequest(ctx context.Context, method, requestURL string, body []byte) (*http.Request, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set headers
+	if body != nil && !c.OmitDefaultContentType {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if !c.OmitDefaultAccept {
+		req.Header.Set("Accept", "application/json")
+	}
+	c.state.headersMu.RLock()
+	if c.AuthSecret != "" && !c.AuthSecretInQuery {
+		req.Header.Set("authsecret", c.AuthSecret)
+	}
+	if c.SessionId != "" {
+		req.Header.Set("X-ss-id", c.SessionId)
+	}
+	for key, value := range c.Headers {
+		req.Header.Set(key, value)
+	}
+	c.state.headersMu.RUnlock()
+	if !c.isTrustedHost(req.URL) {
+		req.Header.Del("Authorization")
+		req.Header.Del("authsecret")
+		req.Header.Del("X-ss-id")
+	}
+	for key, value := range requestHeaders(ctx) {
+		req.Header.Set(key, value)
+	}
+	if cookies, ok := ctx.Value(requestCookiesKey{}).([]*http.Cookie); ok {
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+	}
+
+	return req, nil
+}
+
+// isTrustedHost reports whether the client's credentials can be sent to the
+// URL's host: the host of BaseURL or WriteBaseURL, one of the TrustedHosts,
+// or any host when SendAuthToAllHosts is set
+func (c *JsonServiceClient) isTrustedHost(u *url.URL) bool {
+	if c.SendAuthToAllHosts {
+		return true
+	}
+	for _, baseURL := range []string{c.BaseURL, c.WriteBaseURL} {
+		if baseURL == "" {
+			continue
+		}
+		if base, err := url.Parse(baseURL); err == nil && strings.EqualFold(base.Host, u.Host) {
+			return true
+		}
+	}
+	for _, host := range c.TrustedHosts {
+		if strings.EqualFold(host, u.Host) || strings.EqualFold(host, u.Hostname()) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestCookiesKey is the context key of the cookies for a single request
+type requestCookiesKey struct{}
+
+// requestHeadersKey is the context key of the headers for a single request
+type requestHeadersKey struct{}
+
+// withRequestHeader returns a context that sends the header with the request,
+// overriding the client's headers
+func withRequestHeader(ctx context.Context, key, value string) context.Context {
+	headers := map[string]string{}
+	for k, v := range requestHeaders(ctx) {
+		headers[k] = v
+	}
+	headers[key] = value
+	return context.WithValue(ctx, requestHeadersKey{}, headers)
+}
+
+// requestHeaders returns the headers for a single request stored in ctx
+func requestHeaders(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(requestHeadersKey{}).(map[string]string)
+	return headers
+}
+
+// parseError converts an error response into an error using the ErrorParser
+// if one is configured
+func (c *JsonServiceClient) parseError(resp *http.Response, body []byte) error {
+	if location, err := resp.Location(); err == nil && isLoginURL(location) {
+		return &WebServiceException{
+			StatusCode:        http.StatusUnauthorized,
+			StatusDescription: "Unauthorized",
+			ResponseStatus: &ResponseStatus{
+				ErrorCode: ErrorCodeNotAuthenticated,
+				Message:   "Not Authenticated",
+			},
+			ResponseBody: string(body),
+			Location:     location.String(),
+		}
+	}
+	if c.ErrorParser != nil {
+		return c.ErrorParser(resp.StatusCode, resp.Status, body, resp.Header)
+	}
+	err := parseErrorWith(resp.StatusCode, resp.Status, body, c.unmarshal)
+	if webEx, ok := err.(*WebServiceException); ok {
+		webEx.Location = resp.Header.Get("Location")
+		webEx.CorrelationId = correlationId(resp.Header, webEx.ResponseStatus)
+	}
+	return err
+}
+
+// isLoginURL reports whether the URL is a login page ServiceStack redirects
+// unauthenticated HTML clients to, e.g. /login?redirect=...
+func isLoginURL(u *url.URL) bool {
+	path := strings.ToLower(strings.TrimRight(u.Path, "/"))
+	for _, page := range []string{"/login", "/signin", "/sign-in", "/auth/login"} {
+		if strings.HasSuffix(path, page) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseError converts an error response into a WebServiceException
+func parseError(statusCode int, status string, body []byte) error {
+	return parseErrorWith(statusCode, status, body, json.Unmarshal)
+}
+
+// parseErrorWith converts an error response into a WebServiceException,
+// deserializing its ResponseStatus with unmarshal
+func parseErrorWith(statusCode int, status string, body []byte, unmarshal func([]byte, interface{}) error) error {
+	statusDescription := strings.TrimSpace(strings.TrimPrefix(status, fmt.Sprint(statusCode)))
+	webEx := &WebServiceException{
+		StatusCode:        statusCode,
+		StatusDescription: statusDescription,
+		ResponseBody:      string(body),
+	}
+
+	var errorResponse ErrorResponse
+	if err := unmarshal(body, &errorResponse); err == nil && errorResponse.ResponseStatus != nil {
+		webEx.ResponseStatus = errorResponse.ResponseStatus
+	} else if status := rootResponseStatus(body); status != nil {
+		webEx.ResponseStatus = status
+	} else {
+		webEx.ResponseStatus = &ResponseStatus{
+			ErrorCode: strings.ReplaceAll(statusDescription, " ", ""),
+			Message:   statusDescription,
+		}
+	}
+
+	return webEx
+}
+
+// rootResponseStatus returns the responseStatus property at the root of a JSON
+// error body, e.g. a response DTO with an embedded ResponseStatus that the
+// ErrorResponse envelope couldn't be unmarshalled from
+func rootResponseStatus(body []byte) *ResponseStatus {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(body, &root); err != nil {
+		return nil
+	}
+	for name, value := range root {
+		if !strings.EqualFold(name, "responseStatus") {
+			continue
+		}
+		var status ResponseStatus
+		if err := json.Unmarshal(value, &status); err != nil || (status.ErrorCode == "" && status.Message == "") {
+			return nil
+		}
+		return &status
+	}
+	return nil
+}
+
+// Predefined route prefixes for request DTOs
+const (
+	jsonReplyPrefix = "/json/reply"
+	apiPrefix       = "/api"
+)
+
+// RegisterRoute sends requests of the request DTO's type to path instead of
+// its predefined /json/reply/{Type} route
+func (c *JsonServiceClient) RegisterRoute(requestType interface{}, path string) {
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+
+	if c.state.routes == nil {
+		c.state.routes = make(map[string]string)
+	}
+	c.state.routes[typeName(requestType)] = path
+}
+
+// getRequestPath returns the registered route for the request DTO, the route
+// it declares with IRoute, or its predefined route
+func (c *JsonServiceClient) getRequestPath(request interface{}) string {
+	name := typeName(request)
+
+	c.state.mu.Lock()
+	path, ok := c.state.routes[name]
+	c.state.mu.Unlock()
+	if ok {
+		return path
+	}
+	if route, ok := request.(IRoute); ok && route.Route() != "" {
+		return route.Route()
+	}
+	return c.typePath(name)
+}
+
+// typeRoute returns the registered or predefined route for the type name
+func (c *JsonServiceClient) typeRoute(name string) string {
+	c.state.mu.Lock()
+	path, ok := c.state.routes[name]
+	c.state.mu.Unlock()
+	if ok {
+		return path
+	}
+	return c.typePath(name)
+}
+
+// typePath returns the predefined route for the request DTO type name
+func (c *JsonServiceClient) typePath(name string) string {
+	if c.UseApiEndpoint {
+		return apiPrefix + "/" + name
+	}
+	return jsonReplyPrefix + "/" + name
+}
+
+// typeName returns the name of the request DTO's type, dereferencing pointers
+func typeName(request interface{}) string {
+	t := reflect.TypeOf(request)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	return t.Name()
+}
+
+// toQueryString serializes the non-zero fields of the request DTO into a
+// query string using their JSON names
+func toQueryString(request interface{}) string {
+	var omit map[string]bool
+	if partial, ok := request.(*partialRequest); ok {
+		request, omit = partial.request, partial.omit
+	}
+
+	v := reflect.ValueOf(request)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	values := url.Values{}
+	addQueryFields(values, v, omit)
+	return values.Encode()
+}
+
+// addQueryFields adds the struct's exported non-zero fields to values except
+// the omitted ones, flattening embedded structs and sending map entries as
+// field[key]=value
+func addQueryFields(values url.Values, v reflect.Value, omit map[string]bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || hasTagOption(field, "ignore") {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+		if field.Anonymous && fieldValue.Kind() == reflect.Struct {
+			addQueryFields(values, fieldValue, omit)
+			continue
+		}
+		if omit[field.Name] {
+			continue
+		}
+
+		name, skip := jsonFieldName(field)
+		if skip || fieldValue.IsZero() {
+			continue
+		}
+
+		for fieldValue.Kind() == reflect.Ptr {
+			fieldValue = fieldValue.Elem()
+		}
+
+		if fieldValue.Kind() == reflect.Map {
+			iter := fieldValue.MapRange()
+			for iter.Next() {
+				key := fmt.Sprintf("%v", iter.Key().Interface())
+				values.Set(name+"["+key+"]", queryValue(iter.Value()))
+			}
+			continue
+		}
+
+		// Byte slices are base64 encoded like in JSON
+		if fieldValue.Kind() == reflect.Slice && fieldValue.Type().Elem().Kind() == reflect.Uint8 {
+			values.Set(name, base64.StdEncoding.EncodeToString(fieldValue.Bytes()))
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Slice || fieldValue.Kind() == reflect.Array {
+			items := make([]string, fieldValue.Len())
+			for j := range items {
+				items[j] = queryValue(fieldValue.Index(j))
+			}
+			values.Set(name, strings.Join(items, ","))
+			continue
+		}
+
+		values.Set(name, queryValue(fieldValue))
+	}
+}
+
+// queryValue formats a field value for the query string
+func queryValue(v reflect.Value) string {
+	if name, ok := enumString(v); ok {
+		return name
+	}
+	// Send numeric values like the JSON body rather than their String()
+	if nonEnumTypes[v.Type()] {
+		return strconv.FormatInt(v.Int(), 10)
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// jsonFieldName returns the name a struct field is serialized as in JSON
+func jsonFieldName(field reflect.StructField) (name string, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name = strings.Split(tag, ",")[0]
+	if name == "" {
+		name = field.Name
+	}
+	return name, false
+}
+
+// drainBody reads and closes the response body so its connection can be reused
+func drainBody(resp *http.Response) {
+	if resp != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// joinURL joins the base URL and path with exactly one slash between them.
+// When the base URL already ends with the path's predefined route prefix,
+// e.g. https://host/app/api and /api/Hello, the prefix isn't repeated.
+func joinURL(baseURL, path string) string {
+	if path == "" {
+		return baseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+	path = "/" + strings.TrimLeft(path, "/")
+	for _, prefix := range []string{jsonReplyPrefix, apiPrefix} {
+		if strings.HasSuffix(baseURL, prefix) && strings.HasPrefix(path, prefix+"/") {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	return baseURL + path
+}
+
+// appendQueryParam appends a query param to the URL
+func appendQueryParam(requestURL, name, value string) string {
+	separator := "?"
+	if strings.Contains(requestURL, "?") {
+		separator = "&"
+	}
+	return requestURL + separator + url.QueryEscape(name) + "=" + url.QueryEscape(value)
+}
@@ -0,0 +1,2016 @@
+package servicestack
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Hello struct {
+	Name string `json:"name"`
+}
+
+func (r *Hello) ResponseType() interface{} {
+	return &HelloResponse{}
+}
+
+type HelloResponse struct {
+	Result         string          `json:"result"`
+	ResponseStatus *ResponseStatus `json:"responseStatus,omitempty"`
+}
+
+func TestNewJsonServiceClient(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+
+	if client.BaseURL != "https://api.example.com" {
+		t.Errorf("Expected BaseURL to be 'https://api.example.com', got '%s'", client.BaseURL)
+	}
+
+	if client.HTTPClient == nil {
+		t.Error("Expected HTTPClient to be initialized")
+	}
+
+	if client.Headers == nil {
+		t.Error("Expected Headers to be initialized")
+	}
+}
+
+func TestJsonServiceClientGet(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+
+		if r.URL.Path != "/json/reply/Hello" {
+			t.Errorf("Expected path '/json/reply/Hello', got '%s'", r.URL.Path)
+		}
+
+		if r.URL.Query().Get("name") != "World" {
+			t.Errorf("Expected name query param 'World', got '%s'", r.URL.Query().Get("name"))
+		}
+
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, World!"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result, err := client.Get(&Hello{Name: "World"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response := result.(*HelloResponse)
+	if response.Result != "Hello, World!" {
+		t.Errorf("Expected result 'Hello, World!', got '%s'", response.Result)
+	}
+}
+
+func TestJsonServiceClientPost(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected Content-Type header to be 'application/json', got '%s'", r.Header.Get("Content-Type"))
+		}
+
+		var req Hello
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, " + req.Name + "!"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result, err := client.Post(&Hello{Name: "ServiceStack"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response := result.(*HelloResponse)
+	if response.Result != "Hello, ServiceStack!" {
+		t.Errorf("Expected result 'Hello, ServiceStack!', got '%s'", response.Result)
+	}
+}
+
+type GetHello struct {
+	Name string `json:"name"`
+}
+
+func (r *GetHello) ResponseType() interface{} { return &HelloResponse{} }
+func (r *GetHello) HttpMethod() string        { return http.MethodGet }
+
+type PostHello struct {
+	Name string `json:"name"`
+}
+
+func (r *PostHello) ResponseType() interface{} { return &HelloResponse{} }
+func (r *PostHello) HttpMethod() string        { return http.MethodPost }
+
+type PutHello struct {
+	Name string `json:"name"`
+}
+
+func (r *PutHello) ResponseType() interface{} { return &HelloResponse{} }
+func (r *PutHello) HttpMethod() string        { return http.MethodPut }
+
+type DeleteHello struct {
+	Name string `json:"name"`
+}
+
+func (r *DeleteHello) ResponseType() interface{} { return &HelloResponse{} }
+func (r *DeleteHello) HttpMethod() string        { return http.MethodDelete }
+
+type PatchHello struct {
+	Name string `json:"name"`
+}
+
+func (r *PatchHello) ResponseType() interface{} { return &HelloResponse{} }
+func (r *PatchHello) HttpMethod() string        { return http.MethodPatch }
+
+type LowercaseGetHello struct {
+	Name string `json:"name"`
+}
+
+func (r *LowercaseGetHello) ResponseType() interface{} { return &HelloResponse{} }
+func (r *LowercaseGetHello) HttpMethod() string        { return "get" }
+
+func TestIGetMethodIsCaseInsensitive(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+		if r.URL.Query().Get("name") != "World" {
+			t.Errorf("Expected name 'World' on the query string, got '%s'", r.URL.Query().Get("name"))
+		}
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.Post(&LowercaseGetHello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestSendRequestUsesMarkerMethod(t *testing.T) {
+	var method string
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+
+	tests := []struct {
+		request  IReturn
+		expected string
+	}{
+		{&GetHello{Name: "World"}, http.MethodGet},
+		{&PostHello{Name: "World"}, http.MethodPost},
+		{&PutHello{Name: "World"}, http.MethodPut},
+		{&DeleteHello{Name: "World"}, http.MethodDelete},
+		{&PatchHello{Name: "World"}, http.MethodPatch},
+		{&Hello{Name: "World"}, http.MethodPost},
+	}
+
+	for _, test := range tests {
+		result, err := client.SendRequest(test.request)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if method != test.expected {
+			t.Errorf("Expected %s method for %T, got %s", test.expected, test.request, method)
+		}
+
+		if result.(*HelloResponse).Result != "OK" {
+			t.Errorf("Expected result 'OK', got '%s'", result.(*HelloResponse).Result)
+		}
+	}
+}
+
+func TestJsonServiceClientVerbs(t *testing.T) {
+	var method, query, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, query, body = r.Method, r.URL.RawQuery, string(data)
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	tests := []struct {
+		send           func() (interface{}, error)
+		expectedMethod string
+		expectedQuery  string
+		expectedBody   string
+	}{
+		{func() (interface{}, error) { return client.Get(&Hello{Name: "A"}) }, "GET", "name=A", ""},
+		{func() (interface{}, error) { return client.Delete(&Hello{Name: "A"}) }, "DELETE", "name=A", ""},
+		{func() (interface{}, error) { return client.Post(&Hello{Name: "A"}) }, "POST", "", `{"name":"A"}`},
+		{func() (interface{}, error) { return client.Put(&Hello{Name: "A"}) }, "PUT", "", `{"name":"A"}`},
+		{func() (interface{}, error) { return client.Patch(&Hello{Name: "A"}) }, "PATCH", "", `{"name":"A"}`},
+		{func() (interface{}, error) { return client.Post(&GetHello{Name: "A"}) }, "GET", "name=A", ""},
+	}
+
+	for _, test := range tests {
+		if _, err := test.send(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if method != test.expectedMethod {
+			t.Errorf("Expected %s method, got %s", test.expectedMethod, method)
+		}
+
+		if query != test.expectedQuery {
+			t.Errorf("Expected query '%s' for %s, got '%s'", test.expectedQuery, method, query)
+		}
+
+		if body != test.expectedBody {
+			t.Errorf("Expected body '%s' for %s, got '%s'", test.expectedBody, method, body)
+		}
+	}
+}
+
+func TestJsonServiceClientIncludeQueryOnPost(t *testing.T) {
+	var query, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		query, body = r.URL.RawQuery, string(data)
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.IncludeQueryOnPost = true
+
+	if _, err := client.Post(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if query != "name=World" {
+		t.Errorf("Expected query 'name=World', got '%s'", query)
+	}
+
+	if body != `{"name":"World"}` {
+		t.Errorf("Expected body '{\"name\":\"World\"}', got '%s'", body)
+	}
+}
+
+func TestJsonServiceClientAuthentication(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetBearerToken("token123")
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if authorization != "Bearer token123" {
+		t.Errorf("Expected Authorization header 'Bearer token123', got '%s'", authorization)
+	}
+
+	client.SetCredentials("user", "pass")
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if authorization != "Basic dXNlcjpwYXNz" {
+		t.Errorf("Expected Authorization header 'Basic dXNlcjpwYXNz', got '%s'", authorization)
+	}
+}
+
+func TestJsonServiceClientAuthSecret(t *testing.T) {
+	var header, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("authsecret")
+		query = r.URL.Query().Get("authsecret")
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetAuthSecret("p@55wOrd")
+
+	if _, err := client.Post(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if header != "p@55wOrd" {
+		t.Errorf("Expected authsecret header 'p@55wOrd', got '%s'", header)
+	}
+	if query != "" {
+		t.Errorf("Expected no authsecret query param, got '%s'", query)
+	}
+
+	client.AuthSecretInQuery = true
+	if _, err := client.Get(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query != "p@55wOrd" {
+		t.Errorf("Expected authsecret query param 'p@55wOrd', got '%s'", query)
+	}
+	if header != "" {
+		t.Errorf("Expected no authsecret header, got '%s'", header)
+	}
+}
+
+func TestJsonServiceClientRefererAndOrigin(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Referer") != "https://app.example.com/page" {
+			t.Errorf("Expected Referer 'https://app.example.com/page', got '%s'", r.Header.Get("Referer"))
+		}
+		if r.Header.Get("Origin") != "https://app.example.com" {
+			t.Errorf("Expected Origin 'https://app.example.com', got '%s'", r.Header.Get("Origin"))
+		}
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetReferer("https://app.example.com/page")
+	client.SetOrigin("https://app.example.com")
+
+	if _, err := client.Get(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestJsonServiceClientPing(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected GET method, got %s", r.Method)
+		}
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("OK"))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	if err := client.Ping(); err != nil {
+		t.Errorf("Expected healthy server, got %v", err)
+	}
+
+	client.PingPath = "/health"
+	err := client.Ping()
+	var webEx *WebServiceException
+	if !errors.As(err, &webEx) || webEx.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 WebServiceException, got %v", err)
+	}
+}
+
+func TestJsonServiceClientWarmup(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Expected HEAD method, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	client := NewJsonServiceClient(server.URL)
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	server.Close()
+	if err := client.Warmup(context.Background()); err == nil {
+		t.Error("Expected an error for an unreachable server")
+	}
+}
+
+func TestJsonServiceClientTokenCookie(t *testing.T) {
+	var cookie *http.Cookie
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, _ = r.Cookie(RefreshTokenCookie)
+		http.SetCookie(w, &http.Cookie{Name: TokenCookie, Value: "new-token", Path: "/"})
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetTokenCookie(RefreshTokenCookie, "stored-refresh-token")
+
+	if value := client.GetTokenCookie(RefreshTokenCookie); value != "stored-refresh-token" {
+		t.Errorf("Expected stored cookie 'stored-refresh-token', got '%s'", value)
+	}
+
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cookie == nil || cookie.Value != "stored-refresh-token" {
+		t.Errorf("Expected %s cookie to be sent, got %v", RefreshTokenCookie, cookie)
+	}
+
+	if value := client.GetTokenCookie(TokenCookie); value != "new-token" {
+		t.Errorf("Expected %s cookie 'new-token', got '%s'", TokenCookie, value)
+	}
+}
+
+func TestJsonServiceClientSendAll(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json/reply/Hello[]" {
+			t.Errorf("Expected path '/json/reply/Hello[]', got '%s'", r.URL.Path)
+		}
+
+		var requests []Hello
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+
+		responses := make([]HelloResponse, len(requests))
+		for i, req := range requests {
+			responses[i] = HelloResponse{Result: "Hello, " + req.Name + "!"}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	results, err := client.SendAll([]IReturn{&Hello{Name: "A"}, &Hello{Name: "B"}})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	if results[1].Response.(*HelloResponse).Result != "Hello, B!" {
+		t.Errorf("Expected result 'Hello, B!', got '%s'", results[1].Response.(*HelloResponse).Result)
+	}
+}
+
+func TestJsonServiceClientSendAllIgnoresRegisteredRoute(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json/reply/Hello[]" {
+			t.Errorf("Expected path '/json/reply/Hello[]', got '%s'", r.URL.Path)
+		}
+		w.Write([]byte(`[{"result":"Hello, A!"}]`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RegisterRoute(&Hello{}, "/hello")
+	if _, err := client.SendAll([]IReturn{&Hello{Name: "A"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestJsonServiceClientSendAllPartialFailure(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"result":"Hello, A!"},
+			{"responseStatus":{"errorCode":"ArgumentException","message":"Invalid name"}},
+			{"result":"Hello, C!"}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	results, err := client.SendAll([]IReturn{&Hello{Name: "A"}, &Hello{Name: "!"}, &Hello{Name: "C"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !results[0].IsSuccess() || results[0].Response.(*HelloResponse).Result != "Hello, A!" {
+		t.Errorf("Expected first request to succeed, got %+v", results[0])
+	}
+	if results[1].IsSuccess() || results[1].Error.ResponseStatus.ErrorCode != "ArgumentException" {
+		t.Errorf("Expected second request to fail with ArgumentException, got %+v", results[1])
+	}
+	if results[1].Response != nil {
+		t.Errorf("Expected no response for the failed request, got %v", results[1].Response)
+	}
+	if !results[2].IsSuccess() || results[2].Response.(*HelloResponse).Result != "Hello, C!" {
+		t.Errorf("Expected third request to succeed, got %+v", results[2])
+	}
+
+	if _, err := SendAllTyped[*Hello, HelloResponse](client, []*Hello{{Name: "A"}, {Name: "!"}, {Name: "C"}}); err == nil || err.Error() != "Invalid name" {
+		t.Errorf("Expected SendAllTyped to return 'Invalid name', got %v", err)
+	}
+}
+
+func TestJsonServiceClientInto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HelloResponse{Result: r.Method})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	tests := map[string]func(IReturn, interface{}) error{
+		http.MethodGet:    client.GetInto,
+		http.MethodPost:   client.PostInto,
+		http.MethodPut:    client.PutInto,
+		http.MethodDelete: client.DeleteInto,
+		http.MethodPatch:  client.PatchInto,
+	}
+
+	for method, send := range tests {
+		var response HelloResponse
+		if err := send(&Hello{Name: "World"}, &response); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if response.Result != method {
+			t.Errorf("Expected %s response, got '%s'", method, response.Result)
+		}
+	}
+}
+
+type HelloDetailsResponse struct {
+	Result  string `json:"result"`
+	Details string `json:"details"`
+}
+
+func TestJsonServiceClientSendAs(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"Hello","details":"extended"}`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result, err := client.SendAs(http.MethodPost, &Hello{Name: "World"}, &HelloDetailsResponse{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, ok := result.(*HelloDetailsResponse)
+	if !ok {
+		t.Fatalf("Expected *HelloDetailsResponse, got %T", result)
+	}
+	if response.Details != "extended" {
+		t.Errorf("Expected details 'extended', got '%s'", response.Details)
+	}
+}
+
+func TestJsonServiceClientSendWithSampleValue(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"Hello, World!"}`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	sample := HelloResponse{}
+	result, err := client.Send(http.MethodPost, &Hello{Name: "World"}, sample)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response, ok := result.(*HelloResponse)
+	if !ok {
+		t.Fatalf("Expected *HelloResponse, got %T", result)
+	}
+	if response.Result != "Hello, World!" {
+		t.Errorf("Expected result 'Hello, World!', got '%s'", response.Result)
+	}
+	if sample.Result != "" {
+		t.Errorf("Expected sample value to be left unchanged, got '%s'", sample.Result)
+	}
+}
+
+func TestJsonServiceClientSendVariantsWithSampleValue(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"Hello"}`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	request := &Hello{Name: "World"}
+	sends := map[string]func() (interface{}, error){
+		"SendWithAccept": func() (interface{}, error) {
+			return client.SendWithAccept("application/json", http.MethodPost, request, HelloResponse{})
+		},
+		"SendWithIfMatch": func() (interface{}, error) {
+			return client.SendWithIfMatch(`"v1"`, http.MethodPut, request, HelloResponse{})
+		},
+		"SendWithCookies": func() (interface{}, error) {
+			return client.SendWithCookies(http.MethodPost, request, HelloResponse{}, nil)
+		},
+		"SendTimed": func() (interface{}, error) {
+			result, _, err := client.SendTimed(http.MethodPost, request, HelloResponse{})
+			return result, err
+		},
+		"SendWithQuery": func() (interface{}, error) {
+			return client.SendWithQuery(http.MethodGet, request, HelloResponse{}, QueryParams{})
+		},
+		"SendWithResponse": func() (interface{}, error) {
+			response, err := client.SendWithResponse(http.MethodPost, request, HelloResponse{})
+			if err != nil {
+				return nil, err
+			}
+			return response.Response, nil
+		},
+		"SendMap": func() (interface{}, error) {
+			return client.SendMap(http.MethodPost, "Hello", map[string]interface{}{"name": "World"}, HelloResponse{})
+		},
+		"PostStream": func() (interface{}, error) {
+			return client.PostStream("/uploads", strings.NewReader(`{"name":"World"}`), 0, HelloResponse{})
+		},
+	}
+
+	for name, send := range sends {
+		result, err := send()
+		if err != nil {
+			t.Errorf("Expected no error from %s, got %v", name, err)
+			continue
+		}
+		if response, ok := result.(*HelloResponse); !ok || response.Result != "Hello" {
+			t.Errorf("Expected %s to return a *HelloResponse, got %#v", name, result)
+		}
+	}
+}
+
+func TestJsonServiceClientSendWithAccept(t *testing.T) {
+	var accepts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepts = append(accepts, r.Header.Get("Accept"))
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	accept := "application/vnd.example+json"
+	if _, err := client.SendWithAccept(accept, http.MethodPost, &Hello{}, &HelloResponse{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Post(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if accepts[0] != accept {
+		t.Errorf("Expected Accept '%s', got '%s'", accept, accepts[0])
+	}
+	if accepts[1] != "application/json" {
+		t.Errorf("Expected default Accept 'application/json', got '%s'", accepts[1])
+	}
+}
+
+func TestJsonServiceClientSendWithCookies(t *testing.T) {
+	var cookies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("feature")
+		if err != nil {
+			cookies = append(cookies, "")
+		} else {
+			cookies = append(cookies, cookie.Value)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	feature := []*http.Cookie{{Name: "feature", Value: "beta"}}
+	if _, err := client.SendWithCookies(http.MethodPost, &Hello{}, &HelloResponse{}, feature); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Post(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if cookies[0] != "beta" {
+		t.Errorf("Expected feature cookie 'beta', got '%s'", cookies[0])
+	}
+	if cookies[1] != "" {
+		t.Errorf("Expected feature cookie not to persist, got '%s'", cookies[1])
+	}
+	if client.GetTokenCookie("feature") != "" {
+		t.Errorf("Expected feature cookie not in the jar, got '%s'", client.GetTokenCookie("feature"))
+	}
+}
+
+func TestJsonServiceClientSendTimed(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result, elapsed, err := client.SendTimed(http.MethodPost, &Hello{}, &HelloResponse{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.(*HelloResponse).Result != "Hello" {
+		t.Errorf("Expected result 'Hello', got '%s'", result.(*HelloResponse).Result)
+	}
+	if elapsed < 10*time.Millisecond {
+		t.Errorf("Expected elapsed time of at least 10ms, got %v", elapsed)
+	}
+}
+
+func TestJsonServiceClientPathAndTypedRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			t.Errorf("Expected X-Api-Key header 'key', got '%s'", r.Header.Get("X-Api-Key"))
+		}
+
+		var req Hello
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(HelloResponse{Result: r.Method + " " + r.URL.Path + " " + req.Name})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetHeader("X-Api-Key", "key")
+	ctx := context.Background()
+
+	var response HelloResponse
+	if err := client.PostPath(ctx, "/hello", Hello{Name: "A"}, &response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Result != "POST /hello A" {
+		t.Errorf("Expected result 'POST /hello A', got '%s'", response.Result)
+	}
+
+	if err := client.GetPath(ctx, "/hello", &response); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if response.Result != "GET /hello " {
+		t.Errorf("Expected result 'GET /hello ', got '%s'", response.Result)
+	}
+
+	result, err := client.Post(&Hello{Name: "B"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.(*HelloResponse).Result != "POST /json/reply/Hello B" {
+		t.Errorf("Expected result 'POST /json/reply/Hello B', got '%s'", result.(*HelloResponse).Result)
+	}
+}
+
+func TestJsonServiceClientWithContext(t *testing.T) {
+	requestStarted := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requestStarted)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetHeader("X-Api-Key", "key")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scoped := client.WithContext(ctx)
+	scoped.SetHeader("X-Tenant", "acme")
+
+	if _, ok := client.Headers["X-Tenant"]; ok {
+		t.Error("Expected headers of the copy not to change the client's headers")
+	}
+	if scoped.Headers["X-Api-Key"] != "key" {
+		t.Errorf("Expected copy to inherit X-Api-Key header, got '%s'", scoped.Headers["X-Api-Key"])
+	}
+
+	go func() {
+		<-requestStarted
+		cancel()
+	}()
+
+	_, err := scoped.Get(&Hello{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestJsonServiceClientWithContextClose(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	scoped := client.WithContext(context.Background())
+	scoped.Close()
+
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Errorf("Expected closing a copy not to close the client, got %v", err)
+	}
+
+	client.Close()
+	if _, err := scoped.Get(&Hello{}); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed from a copy of a closed client, got %v", err)
+	}
+}
+
+func TestJsonServiceClientPathContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RetryPolicy = NewRetryPolicy(3, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var response HelloResponse
+	if err := client.GetPath(ctx, "/hello", &response); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context deadline error, got %v", err)
+	}
+}
+
+func TestJsonServiceClientGetJSON(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/stats" {
+			t.Errorf("Expected path '/admin/stats', got '%s'", r.URL.Path)
+		}
+		if r.URL.Query().Get("period") != "day" {
+			t.Errorf("Expected period 'day', got '%s'", r.URL.Query().Get("period"))
+		}
+		w.Write([]byte(`{"requests":42,"server":{"name":"web1","tags":["a","b"]}}`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	response, err := client.GetJSON("/admin/stats", url.Values{"period": {"day"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response["requests"] != float64(42) {
+		t.Errorf("Expected requests 42, got %v", response["requests"])
+	}
+	serverInfo, ok := response["server"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected nested server object, got %T", response["server"])
+	}
+	if serverInfo["name"] != "web1" {
+		t.Errorf("Expected server name 'web1', got %v", serverInfo["name"])
+	}
+	if tags, ok := serverInfo["tags"].([]interface{}); !ok || len(tags) != 2 {
+		t.Errorf("Expected 2 tags, got %v", serverInfo["tags"])
+	}
+}
+
+func TestJsonServiceClientGetScalar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") == "number" {
+			io.WriteString(w, "42")
+			return
+		}
+		io.WriteString(w, `"hello"`)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+
+	var number int
+	if err := client.GetScalar(&Hello{Name: "number"}, &number); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if number != 42 {
+		t.Errorf("Expected 42, got %d", number)
+	}
+
+	var text string
+	if err := client.GetScalar(&Hello{Name: "text"}, &text); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if text != "hello" {
+		t.Errorf("Expected 'hello', got '%s'", text)
+	}
+}
+
+func TestSendAllTyped(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []Hello
+		json.NewDecoder(r.Body).Decode(&requests)
+
+		responses := make([]HelloResponse, len(requests))
+		for i, req := range requests {
+			responses[i] = HelloResponse{Result: "Hello, " + req.Name + "!"}
+		}
+		json.NewEncoder(w).Encode(responses)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	responses, err := SendAllTyped[*Hello, HelloResponse](client, []*Hello{{Name: "A"}, {Name: "B"}, {Name: "C"}})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d", len(responses))
+	}
+
+	if responses[2].Result != "Hello, C!" {
+		t.Errorf("Expected result 'Hello, C!', got '%s'", responses[2].Result)
+	}
+}
+
+func TestSendAllResponseCountMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]HelloResponse{{Result: "Hello, A!"}})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	_, err := SendAllTyped[*Hello, HelloResponse](client, []*Hello{{Name: "A"}, {Name: "B"}})
+
+	if err == nil {
+		t.Fatal("Expected an error for mismatched response count")
+	}
+}
+
+func TestJsonServiceClientPublishAll(t *testing.T) {
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+
+		if r.URL.Path != "/json/oneway/Hello[]" {
+			t.Errorf("Expected path '/json/oneway/Hello[]', got '%s'", r.URL.Path)
+		}
+
+		var requests []Hello
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+
+		if len(requests) != 3 {
+			t.Errorf("Expected 3 requests, got %d", len(requests))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	err := client.PublishAll([]interface{}{&Hello{Name: "A"}, &Hello{Name: "B"}, &Hello{Name: "C"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := client.PublishAll(nil); err != nil {
+		t.Fatalf("Expected no error for empty input, got %v", err)
+	}
+
+	if requestCount != 1 {
+		t.Errorf("Expected 1 request to be sent, got %d", requestCount)
+	}
+}
+
+func TestJsonServiceClientCaseInsensitiveResponse(t *testing.T) {
+	// Create a test server returning PascalCase fields for camelCase json tags
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Result":"Hello","ResponseStatus":{"ErrorCode":"Warning","Message":"Deprecated"}}`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result, err := client.Get(&Hello{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	response := result.(*HelloResponse)
+	if response.Result != "Hello" {
+		t.Errorf("Expected result 'Hello', got '%s'", response.Result)
+	}
+	if response.ResponseStatus == nil || response.ResponseStatus.ErrorCode != "Warning" {
+		t.Errorf("Expected error code 'Warning', got %+v", response.ResponseStatus)
+	}
+}
+
+func TestJsonServiceClientStrictResponseDecoding(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"Hello","unexpected":true}`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error by default, got %v", err)
+	}
+
+	client.StrictResponseDecoding = true
+	_, err := client.Get(&Hello{})
+	if err == nil || !strings.Contains(err.Error(), "unexpected") {
+		t.Errorf("Expected unknown field error in strict mode, got %v", err)
+	}
+}
+
+func TestJsonServiceClientErrorResponse(t *testing.T) {
+	// Create a test server that returns a validation error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"responseStatus":{"errorCode":"ValidationException","message":"Validation failed",`+
+			`"errors":[{"errorCode":"NotEmpty","fieldName":"Name","message":"'Name' must not be empty."}]}}`)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	_, err := client.Post(&Hello{})
+
+	webEx, ok := err.(*WebServiceException)
+	if !ok {
+		t.Fatalf("Expected a WebServiceException, got %v", err)
+	}
+
+	if webEx.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected status code 400, got %d", webEx.StatusCode)
+	}
+
+	if webEx.ResponseStatus.ErrorCode != "ValidationException" {
+		t.Errorf("Expected error code 'ValidationException', got '%s'", webEx.ResponseStatus.ErrorCode)
+	}
+
+	if len(webEx.GetFieldErrors()) != 1 || webEx.GetFieldErrors()[0].FieldName != "Name" {
+		t.Errorf("Expected a field error for 'Name', got %v", webEx.GetFieldErrors())
+	}
+}
+
+func TestJsonServiceClientTreatResponseStatusAsError(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HelloResponse{
+			ResponseStatus: &ResponseStatus{ErrorCode: "NotFound", Message: "Greeting not found"},
+		})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error by default, got %v", err)
+	}
+
+	client.TreatResponseStatusAsError = true
+	_, err := client.Get(&Hello{})
+
+	var webEx *WebServiceException
+	if !errors.As(err, &webEx) {
+		t.Fatalf("Expected WebServiceException, got %v", err)
+	}
+	if webEx.StatusCode != http.StatusOK {
+		t.Errorf("Expected status code 200, got %d", webEx.StatusCode)
+	}
+	if webEx.ResponseStatus.ErrorCode != "NotFound" {
+		t.Errorf("Expected error code 'NotFound', got '%s'", webEx.ResponseStatus.ErrorCode)
+	}
+}
+
+func TestJsonServiceClientPlainErrorResponse(t *testing.T) {
+	// Create a test server that returns a non-ServiceStack error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Not Found"))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	_, err := client.Get(&Hello{})
+
+	webEx, ok := err.(*WebServiceException)
+	if !ok {
+		t.Fatalf("Expected a WebServiceException, got %v", err)
+	}
+
+	if webEx.ResponseStatus.ErrorCode != "NotFound" {
+		t.Errorf("Expected error code 'NotFound', got '%s'", webEx.ResponseStatus.ErrorCode)
+	}
+}
+
+func TestJsonServiceClientBaseURLSlashes(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	for _, baseURL := range []string{server.URL, server.URL + "/", server.URL + "//"} {
+		client := NewJsonServiceClient(baseURL)
+		if _, err := client.Get(&Hello{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if path != "/json/reply/Hello" {
+			t.Errorf("Expected path '/json/reply/Hello' for BaseURL '%s', got '%s'", baseURL, path)
+		}
+	}
+}
+
+func TestJsonServiceClientBasePath(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		baseURL        string
+		useApiEndpoint bool
+		expected       string
+	}{
+		{server.URL + "/api-app", false, "/api-app/json/reply/Hello"},
+		{server.URL + "/api-app/", true, "/api-app/api/Hello"},
+		{server.URL + "/api-app/api", true, "/api-app/api/Hello"},
+	}
+
+	for _, test := range tests {
+		client := NewJsonServiceClient(test.baseURL)
+		client.UseApiEndpoint = test.useApiEndpoint
+		if _, err := client.Get(&Hello{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if path != test.expected {
+			t.Errorf("Expected path '%s' for BaseURL '%s', got '%s'", test.expected, test.baseURL, path)
+		}
+	}
+}
+
+type DeleteHellos struct {
+	Names []string `json:"names"`
+}
+
+func (r *DeleteHellos) ResponseType() interface{} { return &HelloResponse{} }
+
+func (r *DeleteHellos) SendAsBody() bool { return true }
+
+func TestJsonServiceClientDeleteWithBody(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected DELETE method, got %s", r.Method)
+		}
+		if r.URL.RawQuery != "" {
+			t.Errorf("Expected no query string, got '%s'", r.URL.RawQuery)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected Content-Type 'application/json', got '%s'", r.Header.Get("Content-Type"))
+		}
+
+		var request DeleteHellos
+		json.NewDecoder(r.Body).Decode(&request)
+		if len(request.Names) != 2 {
+			t.Errorf("Expected 2 names in the body, got %v", request.Names)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.Delete(&DeleteHellos{Names: []string{"A", "B"}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestJsonServiceClientPreferPostForLargeGets(t *testing.T) {
+	var methods, overrides, names []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		overrides = append(overrides, r.Header.Get("X-Http-Method-Override"))
+
+		var request Hello
+		json.NewDecoder(r.Body).Decode(&request)
+		names = append(names, request.Name+r.URL.Query().Get("name"))
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.PreferPostForLargeGets = true
+	client.MaxGetURLLength = 100
+
+	longName := strings.Repeat("x", 200)
+	if _, err := client.Get(&Hello{Name: longName}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Get(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if methods[0] != http.MethodPost || overrides[0] != http.MethodGet {
+		t.Errorf("Expected long GET sent as POST with override, got %s with override '%s'", methods[0], overrides[0])
+	}
+	if names[0] != longName {
+		t.Errorf("Expected long name in the request body, got '%s'", names[0])
+	}
+	if methods[1] != http.MethodGet || overrides[1] != "" {
+		t.Errorf("Expected short GET sent as GET, got %s with override '%s'", methods[1], overrides[1])
+	}
+	if names[1] != "World" {
+		t.Errorf("Expected name 'World' on the query string, got '%s'", names[1])
+	}
+}
+
+func TestJsonServiceClientFollowRedirects(t *testing.T) {
+	var authorization string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Redirected"})
+	}))
+	defer target.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/json/reply/Hello", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetBearerToken("token123")
+
+	result, err := client.Get(&Hello{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.(*HelloResponse).Result != "Redirected" {
+		t.Errorf("Expected result 'Redirected', got '%s'", result.(*HelloResponse).Result)
+	}
+	if authorization != "" {
+		t.Errorf("Expected Authorization to be stripped on cross-host redirect, got '%s'", authorization)
+	}
+
+	client.FollowRedirects = false
+	_, err = client.Get(&Hello{})
+
+	var webEx *WebServiceException
+	if !errors.As(err, &webEx) {
+		t.Fatalf("Expected WebServiceException, got %v", err)
+	}
+	if webEx.StatusCode != http.StatusFound {
+		t.Errorf("Expected status code 302, got %d", webEx.StatusCode)
+	}
+	if webEx.Location != target.URL+"/json/reply/Hello" {
+		t.Errorf("Expected Location '%s', got '%s'", target.URL+"/json/reply/Hello", webEx.Location)
+	}
+}
+
+func TestJsonServiceClientRedirectStripsSessionId(t *testing.T) {
+	var sessionId string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionId = r.Header.Get("X-ss-id")
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer target.Close()
+
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+"/json/reply/Hello", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.SetSessionId("session")
+
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sessionId != "" {
+		t.Errorf("Expected X-ss-id to be stripped on cross-host redirect, got '%s'", sessionId)
+	}
+
+	client.TrustedHosts = []string{strings.TrimPrefix(target.URL, "http://")}
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sessionId != "session" {
+		t.Errorf("Expected X-ss-id to be sent to a trusted host, got '%s'", sessionId)
+	}
+}
+
+func TestJsonServiceClientRedirectToLogin(t *testing.T) {
+	loginRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			loginRequests++
+			w.Write([]byte("<html>Sign In</html>"))
+			return
+		}
+		http.Redirect(w, r, "/login?redirect="+r.URL.Path, http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	_, err := client.Get(&Hello{})
+
+	if !errors.Is(err, ErrNotAuthenticated) {
+		t.Fatalf("Expected ErrNotAuthenticated, got %v", err)
+	}
+
+	var webEx *WebServiceException
+	if !errors.As(err, &webEx) {
+		t.Fatalf("Expected WebServiceException, got %v", err)
+	}
+	if webEx.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected status code 401, got %d", webEx.StatusCode)
+	}
+	if webEx.ResponseStatus.ErrorCode != "NotAuthenticated" {
+		t.Errorf("Expected error code 'NotAuthenticated', got '%s'", webEx.ResponseStatus.ErrorCode)
+	}
+	if loginRequests != 0 {
+		t.Errorf("Expected the login redirect not to be followed, got %d login requests", loginRequests)
+	}
+}
+
+func TestJsonServiceClientRegisterRoute(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/greetings/hello" {
+			t.Errorf("Expected path '/greetings/hello', got '%s'", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.RegisterRoute(Hello{}, "/greetings/hello")
+
+	if _, err := client.Post(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if path := client.getRequestPath(&HelloDetailsResponse{}); path != "/json/reply/HelloDetailsResponse" {
+		t.Errorf("Expected unregistered types to use '/json/reply/HelloDetailsResponse', got '%s'", path)
+	}
+}
+
+func TestJsonServiceClientUrlFilter(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("v") != "2" {
+			t.Errorf("Expected cache-busting param 'v=2', got '%s'", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient("https://unreachable.example.com")
+	client.UrlFilter = func(url string) string {
+		return strings.Replace(url, "https://unreachable.example.com", server.URL, 1) + "&v=2"
+	}
+
+	if _, err := client.Get(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		baseURL  string
+		path     string
+		expected string
+	}{
+		{"https://host", "/json/reply/X", "https://host/json/reply/X"},
+		{"https://host/", "/json/reply/X", "https://host/json/reply/X"},
+		{"https://host/", "json/reply/X", "https://host/json/reply/X"},
+		{"https://host", "json/reply/X", "https://host/json/reply/X"},
+		{"https://host/api-app", "/json/reply/X", "https://host/api-app/json/reply/X"},
+		{"https://host/api-app/", "/api/X", "https://host/api-app/api/X"},
+		{"https://host/api-app/api", "/api/X", "https://host/api-app/api/X"},
+		{"https://host/api-app/json/reply/", "/json/reply/X", "https://host/api-app/json/reply/X"},
+		{"https://host/apis", "/api/X", "https://host/apis/api/X"},
+	}
+
+	for _, test := range tests {
+		if actual := joinURL(test.baseURL, test.path); actual != test.expected {
+			t.Errorf("Expected joinURL(%q, %q) to be '%s', got '%s'", test.baseURL, test.path, test.expected, actual)
+		}
+	}
+}
+
+func TestJsonServiceClientErrorParser(t *testing.T) {
+	// Create a test server that returns a gateway error envelope
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc123")
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, `{"error":{"code":"RateLimited","message":"Slow down"}}`)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.ErrorParser = func(statusCode int, status string, body []byte, header http.Header) error {
+		var envelope struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(body, &envelope)
+		return &WebServiceException{
+			StatusCode:        statusCode,
+			StatusDescription: status,
+			ResponseStatus: &ResponseStatus{
+				ErrorCode: envelope.Error.Code,
+				Message:   envelope.Error.Message,
+				Meta:      map[string]string{"requestId": header.Get("X-Request-Id")},
+			},
+		}
+	}
+
+	_, err := client.Get(&Hello{})
+
+	webEx, ok := err.(*WebServiceException)
+	if !ok {
+		t.Fatalf("Expected a WebServiceException, got %v", err)
+	}
+
+	if webEx.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected status code 429, got %d", webEx.StatusCode)
+	}
+
+	if webEx.ResponseStatus.ErrorCode != "RateLimited" || webEx.Error() != "Slow down" {
+		t.Errorf("Expected 'RateLimited' error 'Slow down', got '%s' error '%s'", webEx.ResponseStatus.ErrorCode, webEx.Error())
+	}
+
+	if webEx.ResponseStatus.Meta["requestId"] != "abc123" {
+		t.Errorf("Expected requestId 'abc123', got '%s'", webEx.ResponseStatus.Meta["requestId"])
+	}
+}
+
+func TestJsonServiceClientSignRequest(t *testing.T) {
+	sign := func(method, path string, body []byte) string {
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(method + path))
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Signature") != sign(r.Method, r.URL.Path, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	var signedBody []byte
+	client := NewJsonServiceClient(server.URL)
+	client.SignRequest = func(req *http.Request, body []byte) {
+		signedBody = body
+		req.Header.Set("X-Signature", sign(req.Method, req.URL.Path, body))
+	}
+
+	if _, err := client.Post(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(signedBody) != `{"name":"World"}` {
+		t.Errorf("Expected signer to see body '{\"name\":\"World\"}', got '%s'", signedBody)
+	}
+
+	if _, err := client.Get(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if signedBody != nil {
+		t.Errorf("Expected signer to see no body for GET, got '%s'", signedBody)
+	}
+}
+
+func TestJsonServiceClientMaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HelloResponse{Result: strings.Repeat("x", 1024)})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.MaxResponseBytes = 512
+
+	if _, err := client.Get(&Hello{}); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge, got %v", err)
+	}
+
+	client.MaxResponseBytes = 2048
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Errorf("Expected no error within the limit, got %v", err)
+	}
+}
+
+func TestJsonServiceClientGzipResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("Expected Accept-Encoding 'gzip', got '%s'", r.Header.Get("Accept-Encoding"))
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		json.NewEncoder(gz).Encode(HelloResponse{Result: "Hello, World!"})
+		gz.Close()
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result, err := client.Get(&Hello{Name: "World"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response := result.(*HelloResponse); response.Result != "Hello, World!" {
+		t.Errorf("Expected result 'Hello, World!', got '%s'", response.Result)
+	}
+}
+
+func TestJsonServiceClientEmptyGzipResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result, err := client.Get(&Hello{Name: "World"})
+	if err != nil {
+		t.Fatalf("Expected no error for an empty gzip body, got %v", err)
+	}
+
+	if response := result.(*HelloResponse); response.Result != "" {
+		t.Errorf("Expected empty response, got '%s'", response.Result)
+	}
+}
+
+func TestToQueryString(t *testing.T) {
+	type Search struct {
+		Query string   `json:"query"`
+		Skip  int      `json:"skip,omitempty"`
+		Ids   []int    `json:"ids"`
+		Tags  []string `json:"tags"`
+		Note  string   `json:"-"`
+	}
+
+	queryString := toQueryString(&Search{Query: "go", Ids: []int{1, 2}, Tags: []string{"a"}, Note: "x"})
+	if queryString != "ids=1%2C2&query=go&tags=a" {
+		t.Errorf("Expected 'ids=1%%2C2&query=go&tags=a', got '%s'", queryString)
+	}
+}
+
+func TestJsonServiceClientByteSliceFields(t *testing.T) {
+	type Upload struct {
+		Data []byte `json:"data"`
+	}
+	payload := []byte{0x00, 0xff, 'h', 'i'}
+
+	var bodies, queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		queries = append(queries, r.URL.Query().Get("data"))
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.Send(http.MethodGet, &Upload{Data: payload}, &map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.Send(http.MethodPost, &Upload{Data: payload}, &map[string]interface{}{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if queries[0] != "AP9oaQ==" {
+		t.Errorf("Expected base64 query param 'AP9oaQ==', got '%s'", queries[0])
+	}
+	if bodies[1] != `{"data":"AP9oaQ=="}` {
+		t.Errorf("Expected base64 body, got '%s'", bodies[1])
+	}
+}
+
+func TestToQueryStringMapField(t *testing.T) {
+	type Search struct {
+		Query string            `json:"query"`
+		Meta  map[string]string `json:"meta"`
+	}
+
+	queryString := toQueryString(&Search{Query: "go", Meta: map[string]string{"source": "web", "lang": "en"}})
+	if queryString != "meta%5Blang%5D=en&meta%5Bsource%5D=web&query=go" {
+		t.Errorf("Expected 'meta%%5Blang%%5D=en&meta%%5Bsource%%5D=web&query=go', got '%s'", queryString)
+	}
+}
+
+func TestJsonServiceClientClose(t *testing.T) {
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result := client.GetAsync(&Hello{})
+
+	<-started
+	client.Close()
+
+	select {
+	case r := <-result:
+		if !errors.Is(r.Err, ErrClientClosed) {
+			t.Errorf("Expected ErrClientClosed for in-flight request, got %v", r.Err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected in-flight request to be cancelled")
+	}
+
+	if _, err := client.Get(&Hello{}); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("Expected ErrClientClosed after Close, got %v", err)
+	}
+}
+
+func TestJsonServiceClientOmitDefaultHeaders(t *testing.T) {
+	var header http.Header
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.OmitDefaultContentType = true
+	client.OmitDefaultAccept = true
+
+	if _, err := client.Post(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := header["Content-Type"]; ok {
+		t.Errorf("Expected no Content-Type header, got '%s'", header.Get("Content-Type"))
+	}
+	if _, ok := header["Accept"]; ok {
+		t.Errorf("Expected no Accept header, got '%s'", header.Get("Accept"))
+	}
+
+	client.SetHeader("Content-Type", "application/vnd.api+json")
+	client.SetHeader("Accept", "application/vnd.api+json")
+	if _, err := client.Post(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if header.Get("Content-Type") != "application/vnd.api+json" {
+		t.Errorf("Expected Content-Type 'application/vnd.api+json', got '%s'", header.Get("Content-Type"))
+	}
+	if header.Get("Accept") != "application/vnd.api+json" {
+		t.Errorf("Expected Accept 'application/vnd.api+json', got '%s'", header.Get("Accept"))
+	}
+}
+
+func TestJsonServiceClientTextPlainResponse(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if r.URL.Query().Get("name") == "json" {
+			io.WriteString(w, `{"result":"OK"}`)
+			return
+		}
+		io.WriteString(w, "Service Unavailable: maintenance window")
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+
+	_, err := client.Get(&Hello{Name: "text"})
+	if !errors.Is(err, ErrUnexpectedContentType) {
+		t.Fatalf("Expected ErrUnexpectedContentType, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "text/plain") || !strings.Contains(err.Error(), "maintenance window") {
+		t.Errorf("Expected error to describe the response, got '%s'", err.Error())
+	}
+
+	result, err := client.Get(&Hello{Name: "json"})
+	if err != nil {
+		t.Fatalf("Expected no error for JSON sent as text/plain, got %v", err)
+	}
+	if result.(*HelloResponse).Result != "OK" {
+		t.Errorf("Expected result 'OK', got '%s'", result.(*HelloResponse).Result)
+	}
+}
+
+func TestJsonServiceClientOnNonJSONResponse(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		io.WriteString(w, `<HelloResponse><Result>OK</Result></HelloResponse>`)
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	var contentType string
+	client.OnNonJSONResponse = func(ct string, body []byte, response interface{}) error {
+		contentType = ct
+		response.(*HelloResponse).Result = string(body)
+		return nil
+	}
+
+	result, err := client.Get(&Hello{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if contentType != "application/xml" {
+		t.Errorf("Expected content type 'application/xml', got '%s'", contentType)
+	}
+	if !strings.Contains(result.(*HelloResponse).Result, "<Result>OK</Result>") {
+		t.Errorf("Expected raw XML body, got '%s'", result.(*HelloResponse).Result)
+	}
+}
+
+func TestJsonServiceClientSendMap(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json/reply/Hello" {
+			t.Errorf("Expected path '/json/reply/Hello', got '%s'", r.URL.Path)
+		}
+
+		if r.Method == http.MethodGet {
+			if r.URL.Query().Get("name") != "Query" || r.URL.Query().Get("ids") != "1,2" {
+				t.Errorf("Expected query 'ids=1,2&name=Query', got '%s'", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, Query!"})
+			return
+		}
+
+		var request map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		if request["name"] != "World" || request["count"] != float64(2) {
+			t.Errorf("Expected body {name: World, count: 2}, got %v", request)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, World!"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+
+	result, err := client.SendMap(http.MethodPost, "Hello", map[string]interface{}{"name": "World", "count": 2}, &HelloResponse{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.(*HelloResponse).Result != "Hello, World!" {
+		t.Errorf("Expected result 'Hello, World!', got '%s'", result.(*HelloResponse).Result)
+	}
+
+	result, err = client.SendMap(http.MethodGet, "Hello", map[string]interface{}{"name": "Query", "ids": []int{1, 2}}, &HelloResponse{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.(*HelloResponse).Result != "Hello, Query!" {
+		t.Errorf("Expected result 'Hello, Query!', got '%s'", result.(*HelloResponse).Result)
+	}
+}
+
+func TestJsonServiceClientSetWriteBaseURL(t *testing.T) {
+	var reads, writes []string
+	// Create test servers for reads and writes
+	readServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reads = append(reads, r.Method)
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer readServer.Close()
+	writeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writes = append(writes, r.Method)
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer writeServer.Close()
+
+	client := NewJsonServiceClient(readServer.URL)
+	client.SetWriteBaseURL(writeServer.URL)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		if _, err := client.Send(method, &Hello{Name: "World"}, &HelloResponse{}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if strings.Join(reads, ",") != "GET" {
+		t.Errorf("Expected only GET on the read host, got %v", reads)
+	}
+	if strings.Join(writes, ",") != "POST,PUT,PATCH,DELETE" {
+		t.Errorf("Expected POST,PUT,PATCH,DELETE on the write host, got %v", writes)
+	}
+
+	client.SetWriteBaseURL("")
+	if _, err := client.Post(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(reads) != 2 {
+		t.Errorf("Expected writes to use BaseURL when WriteBaseURL is unset, got reads %v", reads)
+	}
+}
+
+func TestParseErrorEnvelopeShapes(t *testing.T) {
+	bodies := map[string]string{
+		"envelope": `{"responseStatus":{"errorCode":"NotFound","message":"Customer not found","errors":[{"fieldName":"Id","message":"Unknown id"}]}}`,
+		"dto":      `{"result":null,"count":0,"ResponseStatus":{"ErrorCode":"NotFound","Message":"Customer not found","Errors":[{"FieldName":"Id","Message":"Unknown id"}]}}`,
+	}
+
+	for shape, body := range bodies {
+		// Unmarshalling the envelope fails with a custom serializer, so the
+		// status is read from the root of the body
+		failing := func(data []byte, v interface{}) error { return errors.New("unsupported") }
+		for _, unmarshal := range []func([]byte, interface{}) error{json.Unmarshal, failing} {
+			err := parseErrorWith(404, "404 Not Found", []byte(body), unmarshal)
+
+			var webEx *WebServiceException
+			if !errors.As(err, &webEx) {
+				t.Fatalf("Expected a WebServiceException for %s, got %v", shape, err)
+			}
+			if webEx.StatusCode != 404 {
+				t.Errorf("Expected status code 404 for %s, got %d", shape, webEx.StatusCode)
+			}
+			if webEx.ResponseStatus.ErrorCode != "NotFound" {
+				t.Errorf("Expected error code 'NotFound' for %s, got '%s'", shape, webEx.ResponseStatus.ErrorCode)
+			}
+			if webEx.ResponseStatus.Message != "Customer not found" {
+				t.Errorf("Expected message 'Customer not found' for %s, got '%s'", shape, webEx.ResponseStatus.Message)
+			}
+			if len(webEx.GetFieldErrors()) != 1 || webEx.GetFieldErrors()[0].FieldName != "Id" {
+				t.Errorf("Expected field error for Id for %s, got %v", shape, webEx.GetFieldErrors())
+			}
+		}
+	}
+}
+
+func TestJsonServiceClientAuthOnlySentToBaseHost(t *testing.T) {
+	var authorization, authSecret string
+	// Create a test server for the rewritten host
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization, authSecret = r.Header.Get("Authorization"), r.Header.Get("authsecret")
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer mirror.Close()
+
+	client := NewJsonServiceClient("http://api.example.com")
+	client.SetBearerToken("secret-token")
+	client.SetAuthSecret("admin-secret")
+	client.UrlFilter = func(requestURL string) string {
+		return strings.Replace(requestURL, "http://api.example.com", mirror.URL, 1)
+	}
+
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if authorization != "" || authSecret != "" {
+		t.Errorf("Expected credentials to be stripped for an off-host request, got '%s' and '%s'", authorization, authSecret)
+	}
+
+	client.TrustedHosts = []string{strings.TrimPrefix(mirror.URL, "http://")}
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if authorization != "Bearer secret-token" || authSecret != "admin-secret" {
+		t.Errorf("Expected credentials for a trusted host, got '%s' and '%s'", authorization, authSecret)
+	}
+}
+
+func TestJsonServiceClientAuthSecretQueryOnlySentToBaseHost(t *testing.T) {
+	var query string
+	// Create a test server for the rewritten host
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	defer mirror.Close()
+
+	client := NewJsonServiceClient("http://api.example.com")
+	client.SetAuthSecret("admin-secret")
+	client.AuthSecretInQuery = true
+	client.UrlFilter = func(requestURL string) string {
+		return strings.Replace(requestURL, "http://api.example.com", mirror.URL, 1)
+	}
+
+	if _, err := client.Get(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query != "name=World" {
+		t.Errorf("Expected authsecret to be removed from the query, got '%s'", query)
+	}
+
+	client.SendAuthToAllHosts = true
+	if _, err := client.Get(&Hello{Name: "World"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(query, "authsecret=admin-secret") {
+		t.Errorf("Expected authsecret when sending auth to all hosts, got '%s'", query)
+	}
+}
+
+type CreateAccount struct {
+	UserName string `json:"userName"`
+	Password string `json:"password" servicestack:"secret"`
+}
+
+func (r *CreateAccount) ResponseType() interface{} { return &HelloResponse{} }
+
+func TestJsonServiceClientCaptureRequestOnError(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("status") == "400" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "hunter2") {
+			t.Errorf("Expected the password to be sent, got %s", body)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"responseStatus":{"errorCode":"NullReferenceException","message":"Object reference not set"}}`))
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.CaptureRequestOnError = true
+
+	_, err := client.Post(&CreateAccount{UserName: "jane", Password: "hunter2"})
+	var webEx *WebServiceException
+	if !errors.As(err, &webEx) {
+		t.Fatalf("Expected a WebServiceException, got %v", err)
+	}
+
+	expected := `{"userName":"jane","password":"[REDACTED]"}`
+	if webEx.RequestBody != expected {
+		t.Errorf("Expected request body %s, got %s", expected, webEx.RequestBody)
+	}
+
+	client.DefaultQueryParams = map[string]string{"status": "400"}
+	client.IncludeQueryOnPost = true
+	_, err = client.Post(&CreateAccount{UserName: "jane", Password: "hunter2"})
+	if !errors.As(err, &webEx) {
+		t.Fatalf("Expected a WebServiceException, got %v", err)
+	}
+	if webEx.RequestBody != "" {
+		t.Errorf("Expected no request body captured for a 4xx, got %s", webEx.RequestBody)
+	}
+}
+
+func TestJsonServiceClientSendWithIfMatch(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Match") != `"v2"` {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Updated"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	result, err := client.SendWithIfMatch(`"v2"`, http.MethodPut, &Hello{Name: "World"}, &HelloResponse{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.(*HelloResponse).Result != "Updated" {
+		t.Errorf("Expected result 'Updated', got '%s'", result.(*HelloResponse).Result)
+	}
+
+	_, err = client.SendWithIfMatch(`"v1"`, http.MethodPut, &Hello{Name: "World"}, &HelloResponse{})
+	if !errors.Is(err, ErrConcurrencyConflict) {
+		t.Errorf("Expected ErrConcurrencyConflict, got %v", err)
+	}
+	if errors.Is(err, ErrNotAuthenticated) {
+		t.Error("Expected a 412 not to match ErrNotAuthenticated")
+	}
+}
@@ -0,0 +1,159 @@
+package servicestack
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Stream sends the request DTO as a GET request for a service returning
+// newline-delimited JSON (JSONL), invoking onItem for each line as it's read
+// without buffering the whole response. Returning an error from onItem stops
+// reading the stream and is returned by Stream.
+func (c *JsonServiceClient) Stream(request IReturn, onItem func(json.RawMessage) error) error {
+	if c.isClosed() {
+		return ErrClientClosed
+	}
+
+	path, pathFields := c.resolveRoute(request)
+	if queryString := toQueryString(&partialRequest{request: request, omit: pathFields}); queryString != "" {
+		path += "?" + queryString
+	}
+	requestURL := c.requestURL(http.MethodGet, path)
+
+	ctx, cancel := c.requestContext(c.defaultContext())
+	defer cancel()
+
+	req, err := c.newRequest(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	if !c.OmitDefaultAccept {
+		req.Header.Set("Accept", "application/jsonl, application/x-ndjson, application/json")
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		if c.isClosed() {
+			return ErrClientClosed
+		}
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		return c.parseError(resp, respBody)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		// The scanner reuses its buffer so each item needs its own copy
+		item := make(json.RawMessage, len(line))
+		copy(item, line)
+		if err := onItem(item); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read response stream: %w", err)
+	}
+	return nil
+}
+
+// PostStream sends the JSON read from body as a POST request to the path
+// relative to BaseURL without buffering it in memory, for very large uploads,
+// and unmarshals the response into responseType. contentLength is the length
+// of the body when known, otherwise 0 sends it with chunked encoding. A nil
+// body sends an empty body. Streamed requests aren't retried or signed with
+// SignRequest.
+func (c *JsonServiceClient) PostStream(path string, body io.Reader, contentLength int64, responseType interface{}) (interface{}, error) {
+	if c.isClosed() {
+		return nil, ErrClientClosed
+	}
+	responseType = responseInstance(responseType)
+
+	ctx, cancel := c.requestContext(c.defaultContext())
+	defer cancel()
+	ctx = context.WithValue(ctx, followRedirectsKey{}, c.FollowRedirects)
+	ctx = context.WithValue(ctx, redirectClientKey{}, c)
+
+	release, err := c.acquireSlot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	req, err := c.newRequest(ctx, http.MethodPost, c.requestURL(http.MethodPost, path), nil)
+	if err != nil {
+		return nil, err
+	}
+	if !c.OmitDefaultContentType {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	// A nil body is sent as an empty body
+	if body != nil {
+		req.ContentLength = contentLength
+		if contentLength <= 0 {
+			req.ContentLength = -1
+		}
+		if c.OnUploadProgress != nil {
+			body = &progressReader{reader: body, total: req.ContentLength, onProgress: c.OnUploadProgress}
+		}
+		req.Body = io.NopCloser(body)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		if c.isClosed() {
+			return nil, ErrClientClosed
+		}
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := c.readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, c.parseError(resp, respBody)
+	}
+
+	if responseType != nil && len(respBody) > 0 {
+		if err := c.decodeResponse(resp.Header.Get("Content-Type"), respBody, responseType); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+	}
+	return responseType, nil
+}
+
+// progressReader reports the bytes read from an upload body to onProgress
+type progressReader struct {
+	reader     io.Reader
+	sent       int64
+	total      int64
+	onProgress func(bytesSent, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.onProgress(r.sent, r.total)
+	}
+	return n, err
+}
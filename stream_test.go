@@ -0,0 +1,184 @@
+package servicestack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func newStreamServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jsonl")
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "{\"result\":\"Item %d\"}\n", i)
+			w.(http.Flusher).Flush()
+		}
+	}))
+}
+
+func TestStream(t *testing.T) {
+	server := newStreamServer(t)
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	var results []string
+	err := client.Stream(&Hello{}, func(item json.RawMessage) error {
+		var response HelloResponse
+		if err := json.Unmarshal(item, &response); err != nil {
+			return err
+		}
+		results = append(results, response.Result)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(results) != 3 || results[0] != "Item 1" || results[2] != "Item 3" {
+		t.Errorf("Expected [Item 1 Item 2 Item 3], got %v", results)
+	}
+}
+
+func TestStreamStopsOnCallbackError(t *testing.T) {
+	server := newStreamServer(t)
+	defer server.Close()
+
+	errStop := errors.New("stop")
+	client := NewJsonServiceClient(server.URL)
+	count := 0
+	err := client.Stream(&Hello{}, func(item json.RawMessage) error {
+		count++
+		return errStop
+	})
+
+	if !errors.Is(err, errStop) {
+		t.Errorf("Expected callback error to be returned, got %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("Expected callback to be invoked once, got %d", count)
+	}
+}
+
+func TestPostStreamFromPipe(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST method, got %s", r.Method)
+		}
+		if r.URL.Path != "/uploads" {
+			t.Errorf("Expected path '/uploads', got '%s'", r.URL.Path)
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected Content-Type 'application/json', got '%s'", r.Header.Get("Content-Type"))
+		}
+
+		var items []Hello
+		if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: fmt.Sprintf("%d items", len(items))})
+	}))
+	defer server.Close()
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.Write([]byte("["))
+		for i := 0; i < 1000; i++ {
+			if i > 0 {
+				writer.Write([]byte(","))
+			}
+			fmt.Fprintf(writer, `{"name":"Item %d"}`, i)
+		}
+		writer.Write([]byte("]"))
+		writer.Close()
+	}()
+
+	client := NewJsonServiceClient(server.URL)
+	result, err := client.PostStream("/uploads", reader, 0, &HelloResponse{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.(*HelloResponse).Result != "1000 items" {
+		t.Errorf("Expected result '1000 items', got '%s'", result.(*HelloResponse).Result)
+	}
+}
+
+func TestPostStreamWithContentLength(t *testing.T) {
+	body := `{"name":"World"}`
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != int64(len(body)) {
+			t.Errorf("Expected Content-Length %d, got %d", len(body), r.ContentLength)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	if _, err := client.PostStream("/uploads", strings.NewReader(body), int64(len(body)), &HelloResponse{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestPostStreamNilBody(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != 0 {
+			t.Errorf("Expected Content-Length 0, got %d", r.ContentLength)
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.OnUploadProgress = func(bytesSent, total int64) {}
+	if _, err := client.PostStream("/uploads", nil, 0, &HelloResponse{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestPostStreamUploadProgress(t *testing.T) {
+	body := `[` + strings.Repeat(`{"name":"World"},`, 99) + `{"name":"World"}]`
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	var sent []int64
+	client := NewJsonServiceClient(server.URL)
+	client.OnUploadProgress = func(bytesSent, total int64) {
+		if total != int64(len(body)) {
+			t.Errorf("Expected total %d, got %d", len(body), total)
+		}
+		sent = append(sent, bytesSent)
+	}
+
+	reader := iotest.HalfReader(strings.NewReader(body))
+	if _, err := client.PostStream("/uploads", reader, int64(len(body)), &HelloResponse{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(sent) < 2 {
+		t.Fatalf("Expected several progress callbacks, got %v", sent)
+	}
+	for i := 1; i < len(sent); i++ {
+		if sent[i] <= sent[i-1] {
+			t.Errorf("Expected increasing byte counts, got %v", sent)
+			break
+		}
+	}
+	if last := sent[len(sent)-1]; last != int64(len(body)) {
+		t.Errorf("Expected %d bytes sent, got %d", len(body), last)
+	}
+}
@@ -0,0 +1,62 @@
+package servicestack
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+)
+
+// SetClientCertificate loads the PEM encoded certificate and private key and
+// presents them to services requiring client certificates (mTLS)
+func (c *JsonServiceClient) SetClientCertificate(certPEM, keyPEM []byte) error {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	transport, err := c.httpTransport()
+	if err != nil {
+		return err
+	}
+	tlsConfig := tlsClientConfig(transport)
+	tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	return nil
+}
+
+// SetSkipTLSVerify disables verification of the server's TLS certificate,
+// e.g. for local development against self-signed certificates. It must not
+// be used in production.
+func (c *JsonServiceClient) SetSkipTLSVerify(skip bool) {
+	transport, err := c.httpTransport()
+	if err != nil {
+		c.logf("servicestack: failed to set skip TLS verify: %v", err)
+		return
+	}
+	tlsClientConfig(transport).InsecureSkipVerify = skip
+	if skip {
+		c.logf("servicestack: TLS certificate verification is disabled, don't use this in production")
+	}
+}
+
+// httpTransport returns the HTTPClient's *http.Transport to configure,
+// replacing the default transport with a copy of it
+func (c *JsonServiceClient) httpTransport() (*http.Transport, error) {
+	switch transport := c.HTTPClient.Transport.(type) {
+	case nil:
+		clone := http.DefaultTransport.(*http.Transport).Clone()
+		c.HTTPClient.Transport = clone
+		return clone, nil
+	case *http.Transport:
+		return transport, nil
+	default:
+		return nil, fmt.Errorf("can't configure custom transport %T", transport)
+	}
+}
+
+// tlsClientConfig returns the transport's TLS config, creating it if needed
+func tlsClientConfig(transport *http.Transport) *tls.Config {
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	return transport.TLSClientConfig
+}
@@ -0,0 +1,111 @@
+package servicestack
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// generateCertificate returns a PEM encoded self-signed certificate and key
+func generateCertificate(t *testing.T) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+func TestJsonServiceClientSetClientCertificate(t *testing.T) {
+	// Create an mTLS test server
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			t.Error("Expected a client certificate")
+		}
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello, " + r.TLS.PeerCertificates[0].Subject.CommonName})
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.HTTPClient.Transport = server.Client().Transport.(*http.Transport).Clone()
+
+	if _, err := client.Get(&Hello{}); err == nil {
+		t.Fatal("Expected an error without a client certificate")
+	}
+
+	certPEM, keyPEM := generateCertificate(t)
+	if err := client.SetClientCertificate(certPEM, keyPEM); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	result, err := client.Get(&Hello{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.(*HelloResponse).Result != "Hello, client" {
+		t.Errorf("Expected result 'Hello, client', got '%s'", result.(*HelloResponse).Result)
+	}
+}
+
+func TestJsonServiceClientSetClientCertificateInvalidPEM(t *testing.T) {
+	client := NewJsonServiceClient("https://api.example.com")
+
+	if err := client.SetClientCertificate([]byte("not a cert"), []byte("not a key")); err == nil {
+		t.Error("Expected an error for malformed PEM")
+	}
+}
+
+func TestJsonServiceClientSetSkipTLSVerify(t *testing.T) {
+	// Create a TLS test server with a self-signed certificate
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HelloResponse{Result: "Hello"})
+	}))
+	defer server.Close()
+
+	logger := &testLogger{}
+	client := NewJsonServiceClient(server.URL)
+	client.Logger = logger
+
+	if _, err := client.Get(&Hello{}); err == nil {
+		t.Fatal("Expected a certificate error without skipping verification")
+	}
+
+	client.SetSkipTLSVerify(true)
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(logger.messages) != 1 {
+		t.Errorf("Expected a warning to be logged, got %v", logger.messages)
+	}
+}
@@ -0,0 +1,78 @@
+package servicestack
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// SetHostOverride connects to the address instead of the host in the request
+// URL, bypassing DNS and any proxy, while keeping the URL's Host header and
+// TLS server name, e.g. to test a staging server with production URLs. The
+// address's port defaults to the URL's port. An empty address restores the
+// default dialer.
+func (c *JsonServiceClient) SetHostOverride(ip string) {
+	transport, err := c.httpTransport()
+	if err != nil {
+		c.logf("servicestack: failed to set host override: %v", err)
+		return
+	}
+
+	if ip == "" {
+		defaults := http.DefaultTransport.(*http.Transport)
+		transport.DialContext = defaults.DialContext
+		transport.Proxy = defaults.Proxy
+		return
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		target := ip
+		if _, _, err := net.SplitHostPort(ip); err != nil {
+			_, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			target = net.JoinHostPort(ip, port)
+		}
+		return dialer.DialContext(ctx, network, target)
+	}
+}
+
+// do sends the HTTP request with the HTTPClient, disabling HTTP/2 on its
+// transport first when ForceHTTP1 is set
+func (c *JsonServiceClient) do(req *http.Request) (*http.Response, error) {
+	if c.ForceHTTP1 {
+		c.disableHTTP2()
+	}
+	return c.HTTPClient.Do(req)
+}
+
+// disableHTTP2 stops the transport from negotiating HTTP/2 by setting its
+// TLSNextProto to an empty map and removing h2 from its TLS NextProtos. Each
+// transport is only configured once.
+func (c *JsonServiceClient) disableHTTP2() {
+	c.state.http1Mu.Lock()
+	defer c.state.http1Mu.Unlock()
+
+	transport, err := c.httpTransport()
+	if err != nil {
+		c.logf("servicestack: failed to force HTTP/1.1: %v", err)
+		return
+	}
+	if transport == c.state.http1Transport {
+		return
+	}
+	transport.ForceAttemptHTTP2 = false
+	transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	if config := transport.TLSClientConfig; config != nil && slices.Contains(config.NextProtos, "h2") {
+		config.NextProtos = slices.DeleteFunc(slices.Clone(config.NextProtos), func(proto string) bool {
+			return proto == "h2"
+		})
+	}
+	c.state.http1Transport = transport
+}
@@ -0,0 +1,111 @@
+package servicestack
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJsonServiceClientSetHostOverride(t *testing.T) {
+	var host string
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	client := NewJsonServiceClient("http://staging.invalid:" + port)
+	client.SetHostOverride("127.0.0.1")
+
+	result, err := client.Get(&Hello{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.(*HelloResponse).Result != "OK" {
+		t.Errorf("Expected result 'OK', got '%s'", result.(*HelloResponse).Result)
+	}
+
+	if host != "staging.invalid:"+port {
+		t.Errorf("Expected Host 'staging.invalid:%s', got '%s'", port, host)
+	}
+}
+
+func TestJsonServiceClientSetHostOverrideWithPort(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HelloResponse{Result: "OK"})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient("http://staging.invalid")
+	client.SetHostOverride(server.Listener.Addr().String())
+
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestJsonServiceClientForceHTTP1(t *testing.T) {
+	var protocol string
+	// Create an HTTP/2 capable test server
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protocol = r.Proto
+		json.NewEncoder(w).Encode(HelloResponse{})
+	}))
+	server.EnableHTTP2 = true
+	server.TLS = &tls.Config{NextProtos: []string{"h2", "http/1.1"}}
+	server.StartTLS()
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.HTTPClient.Transport = server.Client().Transport.(*http.Transport).Clone()
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if protocol != "HTTP/2.0" {
+		t.Fatalf("Expected the test server to negotiate HTTP/2.0, got %s", protocol)
+	}
+
+	client = NewJsonServiceClient(server.URL)
+	client.HTTPClient.Transport = server.Client().Transport.(*http.Transport).Clone()
+	client.ForceHTTP1 = true
+	if _, err := client.Get(&Hello{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if protocol != "HTTP/1.1" {
+		t.Errorf("Expected HTTP/1.1 when forced, got %s", protocol)
+	}
+}
+
+func TestJsonServiceClientForceHTTP1GetAppMetadata(t *testing.T) {
+	// Create a test server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(AppMetadata{App: AppInfo{ServiceName: "Test"}})
+	}))
+	defer server.Close()
+
+	client := NewJsonServiceClient(server.URL)
+	client.ForceHTTP1 = true
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetAppMetadata()
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected GetAppMetadata not to deadlock with ForceHTTP1")
+	}
+}
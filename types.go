@@ -0,0 +1,215 @@
+package servicestack
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IReturn is implemented by request DTOs to declare their response type
+type IReturn interface {
+	ResponseType() interface{}
+}
+
+// IGet, IPost, IPut, IDelete and IPatch name the verb a request DTO is sent
+// with for readability only. They aren't distinct markers: they have the same
+// method set, so a DTO implementing one implements them all and they can't be
+// told apart by a type assertion or switch. Dispatch depends only on the value
+// HttpMethod returns, compared case-insensitively.
+
+// IGet is implemented by request DTOs for GET-only services whose HttpMethod
+// returns "GET". They're sent as GET requests with their fields on the query
+// string regardless of the method they're sent with.
+type IGet interface {
+	IReturn
+	HttpMethod() string
+}
+
+// IPost is named for request DTOs whose HttpMethod returns "POST"
+type IPost interface {
+	IReturn
+	HttpMethod() string
+}
+
+// IPut is named for request DTOs whose HttpMethod returns "PUT"
+type IPut interface {
+	IReturn
+	HttpMethod() string
+}
+
+// IDelete is named for request DTOs whose HttpMethod returns "DELETE"
+type IDelete interface {
+	IReturn
+	HttpMethod() string
+}
+
+// IPatch is named for request DTOs whose HttpMethod returns "PATCH"
+type IPatch interface {
+	IReturn
+	HttpMethod() string
+}
+
+// IRoute is implemented by request DTOs that declare their own route, e.g.
+// "/customers/{Id}", which is used instead of their predefined
+// /json/reply/{Type} route. DTOs can also declare the verb the route is
+// called with by implementing RouteVerb() string.
+type IRoute interface {
+	Route() string
+}
+
+// ISendAsBody is implemented by request DTOs that are sent as the JSON body
+// of GET and DELETE requests instead of on the query string, e.g. deletes
+// with large filter payloads
+type ISendAsBody interface {
+	SendAsBody() bool
+}
+
+// httpMethodOf returns the HTTP method declared by the request DTO's marker
+// interface or IRoute RouteVerb, or fallback when it doesn't declare one
+func httpMethodOf(request interface{}, fallback string) string {
+	if marker, ok := request.(interface{ HttpMethod() string }); ok {
+		if method := strings.ToUpper(marker.HttpMethod()); method != "" {
+			return method
+		}
+	}
+	if route, ok := request.(interface{ RouteVerb() string }); ok {
+		if method := strings.ToUpper(route.RouteVerb()); method != "" {
+			return method
+		}
+	}
+	return fallback
+}
+
+// Error codes of well-known ServiceStack exceptions, returned in the
+// ResponseStatus ErrorCode
+const (
+	ErrorCodeValidation            = "ValidationException"
+	ErrorCodeArgument              = "ArgumentException"
+	ErrorCodeArgumentNull          = "ArgumentNullException"
+	ErrorCodeNotFound              = "NotFoundException"
+	ErrorCodeUnauthorizedAccess    = "UnauthorizedAccessException"
+	ErrorCodeOptimisticConcurrency = "OptimisticConcurrencyException"
+	ErrorCodeNotImplemented        = "NotImplementedException"
+	ErrorCodeNotAuthenticated      = "NotAuthenticated"
+)
+
+// ResponseStatus is the ServiceStack error response status
+type ResponseStatus struct {
+	ErrorCode  string            `json:"errorCode,omitempty"`
+	Message    string            `json:"message,omitempty"`
+	StackTrace string            `json:"stackTrace,omitempty"`
+	Errors     []ResponseError   `json:"errors,omitempty"`
+	Meta       map[string]string `json:"meta,omitempty"`
+}
+
+// IsSuccess reports whether the status doesn't contain an error
+func (s *ResponseStatus) IsSuccess() bool {
+	return s == nil || s.ErrorCode == ""
+}
+
+// ResponseError is a field-level validation error
+type ResponseError struct {
+	ErrorCode string            `json:"errorCode,omitempty"`
+	FieldName string            `json:"fieldName,omitempty"`
+	Message   string            `json:"message,omitempty"`
+	Meta      map[string]string `json:"meta,omitempty"`
+}
+
+// ErrorResponse is the envelope ServiceStack uses for error responses
+type ErrorResponse struct {
+	ResponseStatus *ResponseStatus `json:"responseStatus,omitempty"`
+}
+
+// WebServiceException is returned when a ServiceStack service responds with an error
+type WebServiceException struct {
+	StatusCode        int
+	StatusDescription string
+	ResponseStatus    *ResponseStatus
+	ResponseBody      string
+	// Location is the Location header of redirect responses
+	Location string
+	// CorrelationId is the X-Correlation-Id header, or the correlationId in
+	// the ResponseStatus Meta, for cross-referencing server logs
+	CorrelationId string
+	// RequestBody is the JSON request body of 5xx errors when the client's
+	// CaptureRequestOnError is set, with secret fields redacted
+	RequestBody string
+}
+
+// Error implements the error interface
+func (e *WebServiceException) Error() string {
+	if e.ResponseStatus != nil && e.ResponseStatus.Message != "" {
+		return e.ResponseStatus.Message
+	}
+	if e.StatusDescription != "" {
+		return e.StatusDescription
+	}
+	return fmt.Sprintf("request failed with status %d", e.StatusCode)
+}
+
+// Is reports whether the exception matches the target error, e.g.
+// errors.Is(err, ErrNotAuthenticated) for 401 Unauthorized responses and
+// errors.Is(err, ErrConcurrencyConflict) for 412 Precondition Failed
+func (e *WebServiceException) Is(target error) bool {
+	switch target {
+	case ErrNotAuthenticated:
+		return e.StatusCode == 401
+	case ErrConcurrencyConflict:
+		return e.StatusCode == 412
+	}
+	return false
+}
+
+// IsErrorCode reports whether the exception's ResponseStatus has the error
+// code, e.g. IsErrorCode(ErrorCodeOptimisticConcurrency). Codes are compared
+// ignoring case and underscores so "Not_Found" matches "NotFound".
+func (e *WebServiceException) IsErrorCode(code string) bool {
+	return e.ResponseStatus != nil && normalizeErrorCode(e.ResponseStatus.ErrorCode) == normalizeErrorCode(code)
+}
+
+// normalizeErrorCode returns the error code in lowercase without underscores,
+// which servers return inconsistently across versions
+func normalizeErrorCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "_", ""))
+}
+
+// GetFieldErrors returns the field-level validation errors, if any
+func (e *WebServiceException) GetFieldErrors() []ResponseError {
+	if e.ResponseStatus == nil {
+		return nil
+	}
+	return e.ResponseStatus.Errors
+}
+
+// Meta returns the value of the key in the ResponseStatus Meta, e.g. a
+// correlation id or documentation URL
+func (e *WebServiceException) Meta(key string) (string, bool) {
+	if e.ResponseStatus == nil {
+		return "", false
+	}
+	value, ok := e.ResponseStatus.Meta[key]
+	return value, ok
+}
+
+// GetFieldMeta returns the value of the key in the Meta of the field error
+func (e *WebServiceException) GetFieldMeta(fieldName, key string) (string, bool) {
+	for _, fieldError := range e.GetFieldErrors() {
+		if fieldError.FieldName == fieldName {
+			value, ok := fieldError.Meta[key]
+			return value, ok
+		}
+	}
+	return "", false
+}
+
+// Summary returns the error message followed by the messages of its field
+// errors, e.g. "Name is required; Email is invalid", for displaying in UIs
+func (e *WebServiceException) Summary() string {
+	message := e.Error()
+	messages := []string{message}
+	for _, fieldError := range e.GetFieldErrors() {
+		if fieldError.Message != "" && fieldError.Message != message {
+			messages = append(messages, fieldError.Message)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
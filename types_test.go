@@ -0,0 +1,118 @@
+package servicestack
+
+import "testing"
+
+func TestWebServiceExceptionSummary(t *testing.T) {
+	webEx := &WebServiceException{
+		StatusCode: 400,
+		ResponseStatus: &ResponseStatus{
+			ErrorCode: "ValidationException",
+			Message:   "Name is required",
+			Errors: []ResponseError{
+				{ErrorCode: "NotEmpty", FieldName: "Name", Message: "Name is required"},
+				{ErrorCode: "Email", FieldName: "Email", Message: "Email is invalid"},
+				{ErrorCode: "GreaterThan", FieldName: "Age", Message: "Age must be greater than 0"},
+			},
+		},
+	}
+
+	expected := "Name is required; Email is invalid; Age must be greater than 0"
+	if webEx.Summary() != expected {
+		t.Errorf("Expected summary '%s', got '%s'", expected, webEx.Summary())
+	}
+}
+
+func TestWebServiceExceptionSummaryWithoutFieldErrors(t *testing.T) {
+	webEx := &WebServiceException{StatusCode: 500, StatusDescription: "Internal Server Error"}
+
+	if webEx.Summary() != "Internal Server Error" {
+		t.Errorf("Expected summary 'Internal Server Error', got '%s'", webEx.Summary())
+	}
+}
+
+func TestWebServiceExceptionMeta(t *testing.T) {
+	webEx := &WebServiceException{
+		StatusCode: 400,
+		ResponseStatus: &ResponseStatus{
+			ErrorCode: "ValidationException",
+			Meta:      map[string]string{"CorrelationId": "abc123"},
+			Errors: []ResponseError{
+				{FieldName: "Email", Message: "Email is invalid", Meta: map[string]string{"HelpUrl": "https://example.com/email"}},
+			},
+		},
+	}
+
+	if value, ok := webEx.Meta("CorrelationId"); !ok || value != "abc123" {
+		t.Errorf("Expected CorrelationId 'abc123', got '%s'", value)
+	}
+	if _, ok := webEx.Meta("Missing"); ok {
+		t.Error("Expected missing meta key not to be found")
+	}
+
+	if value, ok := webEx.GetFieldMeta("Email", "HelpUrl"); !ok || value != "https://example.com/email" {
+		t.Errorf("Expected HelpUrl 'https://example.com/email', got '%s'", value)
+	}
+	if _, ok := webEx.GetFieldMeta("Name", "HelpUrl"); ok {
+		t.Error("Expected meta of a missing field not to be found")
+	}
+
+	if _, ok := (&WebServiceException{}).Meta("CorrelationId"); ok {
+		t.Error("Expected no meta without a ResponseStatus")
+	}
+}
+
+func TestWebServiceExceptionIsErrorCode(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		errorCode  string
+		expected   string
+	}{
+		{409, "OptimisticConcurrencyException", ErrorCodeOptimisticConcurrency},
+		{404, "NotFoundException", ErrorCodeNotFound},
+		{403, "UnauthorizedAccessException", ErrorCodeUnauthorizedAccess},
+		{400, "ValidationException", ErrorCodeValidation},
+		{400, "ArgumentNullException", ErrorCodeArgumentNull},
+	}
+
+	for _, test := range tests {
+		webEx := &WebServiceException{
+			StatusCode:     test.statusCode,
+			ResponseStatus: &ResponseStatus{ErrorCode: test.errorCode},
+		}
+
+		if !webEx.IsErrorCode(test.expected) {
+			t.Errorf("Expected error code '%s' to match %s", test.errorCode, test.expected)
+		}
+
+		if webEx.IsErrorCode(ErrorCodeNotImplemented) {
+			t.Errorf("Expected error code '%s' not to match %s", test.errorCode, ErrorCodeNotImplemented)
+		}
+	}
+
+	if (&WebServiceException{StatusCode: 500}).IsErrorCode(ErrorCodeValidation) {
+		t.Error("Expected no error code to match without a ResponseStatus")
+	}
+}
+
+func TestWebServiceExceptionIsErrorCodeNormalizesCase(t *testing.T) {
+	tests := []struct {
+		errorCode string
+		code      string
+		expected  bool
+	}{
+		{"Not_Found", "NotFound", true},
+		{"NOT_FOUND", "NotFound", true},
+		{"notfound", "NotFound", true},
+		{"validationexception", ErrorCodeValidation, true},
+		{"Optimistic_Concurrency_Exception", ErrorCodeOptimisticConcurrency, true},
+		{"Not_Found", "Forbidden", false},
+	}
+
+	for _, test := range tests {
+		webEx := &WebServiceException{ResponseStatus: &ResponseStatus{ErrorCode: test.errorCode}}
+
+		if actual := webEx.IsErrorCode(test.code); actual != test.expected {
+			t.Errorf("Expected IsErrorCode(%q) for %q to be %v, got %v", test.code, test.errorCode, test.expected, actual)
+		}
+	}
+}